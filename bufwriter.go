@@ -0,0 +1,227 @@
+package gonbt
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"sync"
+)
+
+// bufferSize of the BufferedWriter before it flushes its buffer
+const bufferSize = 32 << 10
+
+// maxPooledBuffer is the largest buffer kept by Reset, the buffers grown by the large arrays are released
+const maxPooledBuffer = 1 << 20
+
+// BufferedWriter nbt which encode into a reusable buffer, the buffer is written to the output
+// when it's full and on Flush. The arrays are encoded and written in one call.
+// A BufferedWriter can be Reset to write to another output
+type BufferedWriter struct {
+	w     io.Writer
+	buf   []byte
+	order binary.ByteOrder
+	// mutf8 encode the strings with the java modified UTF-8
+	mutf8 bool
+	err   error
+}
+
+// NewBufferedWriter nbt to w with the byte order, binary.BigEndian for the java edition
+// and binary.LittleEndian for the bedrock edition
+func NewBufferedWriter(w io.Writer, order binary.ByteOrder) *BufferedWriter {
+	return &BufferedWriter{
+		w:     w,
+		buf:   make([]byte, 0, bufferSize),
+		order: order,
+		mutf8: order == binary.BigEndian,
+	}
+}
+
+// Reset discard the buffered data and the error, and write to w
+func (w *BufferedWriter) Reset(out io.Writer) {
+	if cap(w.buf) > maxPooledBuffer {
+		w.buf = make([]byte, 0, bufferSize)
+	}
+	w.w = out
+	w.buf = w.buf[:0]
+	w.err = nil
+}
+
+// Flush write the buffered data to the output
+func (w *BufferedWriter) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	if len(w.buf) == 0 {
+		return nil
+	}
+	if _, w.err = w.w.Write(w.buf); w.err != nil {
+		return w.err
+	}
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// grow the buffer of n bytes and return them
+func (w *BufferedWriter) grow(n int) []byte {
+	l := len(w.buf)
+	w.buf = append(w.buf, make([]byte, n)...)
+	return w.buf[l:]
+}
+
+// done flush the buffer when it's full
+func (w *BufferedWriter) done() error {
+	if w.err != nil {
+		return w.err
+	}
+	if len(w.buf) >= bufferSize {
+		return w.Flush()
+	}
+	return nil
+}
+
+// String write with nbt format
+func (w *BufferedWriter) String(str string) error {
+	if w.mutf8 && plainMUTF8(str) < len(str) {
+		str = string(encodeMUTF8(str))
+	}
+	if len(str) > math.MaxUint16 {
+		return errors.New(errorStringLength)
+	}
+	w.order.PutUint16(w.grow(2), uint16(len(str)))
+	w.buf = append(w.buf, str...)
+	return w.done()
+}
+
+// Byte write with nbt format
+func (w *BufferedWriter) Byte(b byte) error {
+	w.buf = append(w.buf, b)
+	return w.done()
+}
+
+// Short write with nbt format
+func (w *BufferedWriter) Short(v int16) error {
+	w.order.PutUint16(w.grow(2), uint16(v))
+	return w.done()
+}
+
+// Int write with nbt format
+func (w *BufferedWriter) Int(v int32) error {
+	w.order.PutUint32(w.grow(4), uint32(v))
+	return w.done()
+}
+
+// Long write with nbt format
+func (w *BufferedWriter) Long(v int64) error {
+	w.order.PutUint64(w.grow(8), uint64(v))
+	return w.done()
+}
+
+// Float write with nbt format
+func (w *BufferedWriter) Float(v float32) error {
+	w.order.PutUint32(w.grow(4), math.Float32bits(v))
+	return w.done()
+}
+
+// Double write with nbt format
+func (w *BufferedWriter) Double(v float64) error {
+	w.order.PutUint64(w.grow(8), math.Float64bits(v))
+	return w.done()
+}
+
+// Bytes write with nbt format, the large arrays are written without copy
+func (w *BufferedWriter) Bytes(v []byte) error {
+	w.order.PutUint32(w.grow(4), uint32(len(v)))
+	if len(v) < bufferSize {
+		w.buf = append(w.buf, v...)
+		return w.done()
+	}
+	if w.Flush() != nil {
+		return w.err
+	}
+	if _, w.err = w.w.Write(v); w.err != nil {
+		return w.err
+	}
+	return nil
+}
+
+// IntArray write with nbt format
+func (w *BufferedWriter) IntArray(values []int32) error {
+	b := w.grow(4 + len(values)*4)
+	w.order.PutUint32(b, uint32(len(values)))
+	for i, v := range values {
+		w.order.PutUint32(b[4+i*4:], uint32(v))
+	}
+	return w.done()
+}
+
+// LongArray write with nbt format
+func (w *BufferedWriter) LongArray(values []int64) error {
+	b := w.grow(4 + len(values)*8)
+	w.order.PutUint32(b, uint32(len(values)))
+	for i, v := range values {
+		w.order.PutUint64(b[4+i*8:], uint64(v))
+	}
+	return w.done()
+}
+
+// bufferedWriters pool the BufferedWriter of the encoders
+var bufferedWriters = sync.Pool{
+	New: func() interface{} {
+		return NewBufferedWriter(nil, binary.BigEndian)
+	},
+}
+
+// getBufferedWriter return a BufferedWriter of the pool which write to out
+func getBufferedWriter(out io.Writer, order binary.ByteOrder) *BufferedWriter {
+	w := bufferedWriters.Get().(*BufferedWriter)
+	w.Reset(out)
+	w.order = order
+	w.mutf8 = order == binary.BigEndian
+	return w
+}
+
+// putBufferedWriter release w to the pool
+func putBufferedWriter(w *BufferedWriter) {
+	w.Reset(nil)
+	bufferedWriters.Put(w)
+}
+
+// compressor is the gzip or zlib writer which can be reused
+type compressor interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// compressorKey identify the pools of compressors
+type compressorKey struct {
+	compress string
+	level    int
+}
+
+// compressors pool the gzip and zlib writers by compression type and level
+var compressors sync.Map
+
+// getCompressor return a gzip or zlib writer to w, from the pool when it's possible
+func getCompressor(w io.Writer, compress string, level int) (compressor, error) {
+	var err error
+	var cw io.WriteCloser
+
+	if pool, ok := compressors.Load(compressorKey{compress: compress, level: level}); ok {
+		if c, ok := pool.(*sync.Pool).Get().(compressor); ok {
+			c.Reset(w)
+			return c, nil
+		}
+	}
+	if cw, err = newCompressor(w, compress, level); err != nil {
+		return nil, err
+	}
+	return cw.(compressor), nil
+}
+
+// putCompressor release the closed compressor c to the pool
+func putCompressor(c compressor, compress string, level int) {
+	pool, _ := compressors.LoadOrStore(compressorKey{compress: compress, level: level}, &sync.Pool{})
+	c.Reset(nil)
+	pool.(*sync.Pool).Put(c)
+}
@@ -0,0 +1,138 @@
+package gonbt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failWriter fail all the writes
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestBufferedWriter(t *testing.T) {
+	t.Run("should be ok with the same data as the writer", func(t *testing.T) {
+		tag := newBenchChunk()
+		tag.Set("Big", &ByteArrayT{Value: make([]byte, bufferSize*2)})
+		tag.Set("Text", &StringT{Value: "\x00😀"})
+		tag.Set("Double", &DoubleT{Value: -0.5})
+
+		for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+			var expected, buf bytes.Buffer
+			assert.NoError(t, tag.Write(NewWriterOrder(&expected, order), true))
+
+			w := NewBufferedWriter(&buf, order)
+			if assert.NoError(t, tag.Write(w, true)) && assert.NoError(t, w.Flush()) {
+				assert.EqualValues(t, expected.Bytes(), buf.Bytes())
+			}
+		}
+	})
+	t.Run("should be ok and keep the data in the buffer until Flush", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewBufferedWriter(&buf, binary.BigEndian)
+
+		assert.NoError(t, w.IntArray([]int32{1, -1}))
+		assert.Empty(t, buf.Bytes())
+		if assert.NoError(t, w.Flush()) {
+			assert.EqualValues(t, []byte{0, 0, 0, 2, 0, 0, 0, 1, 0xff, 0xff, 0xff, 0xff}, buf.Bytes())
+		}
+	})
+	t.Run("should be ok with a Reset to another output", func(t *testing.T) {
+		var first, second bytes.Buffer
+		w := NewBufferedWriter(&first, binary.BigEndian)
+
+		assert.NoError(t, w.Short(1))
+		w.Reset(&second)
+		assert.NoError(t, w.Short(2))
+		assert.NoError(t, w.Flush())
+		assert.Empty(t, first.Bytes())
+		assert.EqualValues(t, []byte{0, 2}, second.Bytes())
+	})
+	t.Run("should return the error of the output until Reset", func(t *testing.T) {
+		w := NewBufferedWriter(failWriter{}, binary.BigEndian)
+
+		assert.Error(t, w.LongArray(make([]int64, bufferSize/8)))
+		assert.Error(t, w.Byte(1))
+		assert.Error(t, w.Flush())
+		w.Reset(ioutil.Discard)
+		assert.NoError(t, w.Byte(1))
+		assert.NoError(t, w.Flush())
+	})
+	t.Run("should return an error because the string is too long", func(t *testing.T) {
+		w := NewBufferedWriter(ioutil.Discard, binary.BigEndian)
+
+		err := w.String(string(make([]byte, 1<<16)))
+		if assert.Error(t, err) {
+			assert.EqualValues(t, errorStringLength, err.Error())
+		}
+	})
+}
+
+func TestEncoder_Close(t *testing.T) {
+	t.Run("should return an error because the encoder is closed", func(t *testing.T) {
+		encoder := NewEncoder(ioutil.Discard)
+
+		assert.NoError(t, encoder.Encode(newTestTag()))
+		assert.NoError(t, encoder.Close())
+		assert.NoError(t, encoder.Close())
+		err := encoder.Encode(newTestTag())
+		if assert.Error(t, err) {
+			assert.EqualValues(t, errorStreamClosed, err.Error())
+		}
+	})
+	t.Run("should be ok with the pooled compressors on many Marshal", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			for _, compress := range []string{CompressGZIP, CompressZLIB} {
+				data, err := Marshal(newBenchChunk(), compress)
+				if assert.NoError(t, err) {
+					tag, err := Unmarshal(data)
+					if assert.NoError(t, err) {
+						assert.EqualValues(t, newBenchChunk(), tag)
+					}
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkWriter(b *testing.B) {
+	tag := newBenchChunk()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := tag.Write(NewWriter(ioutil.Discard), true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBufferedWriter(b *testing.B) {
+	tag := newBenchChunk()
+	w := NewBufferedWriter(ioutil.Discard, binary.BigEndian)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		w.Reset(ioutil.Discard)
+		if err := tag.Write(w, true); err != nil {
+			b.Fatal(err)
+		}
+		if err := w.Flush(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalZLIB(b *testing.B) {
+	tag := newBenchChunk()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := Marshal(tag, CompressZLIB); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package gonbt
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// bytesReader read the nbt from a byte slice with a cursor, the primitives are
+// decoded without allocation and the arrays in one pass
+type bytesReader struct {
+	data  []byte
+	pos   int
+	order binary.ByteOrder
+	// mutf8 decode the strings with the java modified UTF-8
+	mutf8 bool
+	// raw keep the bytes of the malformed strings rather than return an error
+	raw bool
+	// alias return the byte arrays as sub-slices of data rather than copies
+	alias bool
+	// cut is set when data is cut to the limit MaxBytes
+	cut bool
+	limiter
+}
+
+// NewBytesReader nbt which decode data in place with the byte order,
+// binary.BigEndian for the java edition and binary.LittleEndian for the bedrock edition
+func NewBytesReader(data []byte, order binary.ByteOrder) Reader {
+	return newBytesReader(data, order, DefaultLimits())
+}
+
+// newBytesReader return the reader of data with the limits
+func newBytesReader(data []byte, order binary.ByteOrder, limits Limits) *bytesReader {
+	r := &bytesReader{
+		data:    data,
+		order:   order,
+		mutf8:   order == binary.BigEndian,
+		limiter: limiter{limits: limits},
+	}
+	if limits.MaxBytes > 0 && int64(len(data)) > limits.MaxBytes {
+		r.data = data[:limits.MaxBytes]
+		r.cut = true
+	}
+	return r
+}
+
+// end return the error at the end of data
+func (r *bytesReader) end(err error) error {
+	if r.cut {
+		return ErrMaxBytes
+	}
+	return err
+}
+
+// next return the n next bytes of data and move the cursor, the cursor is kept
+// at the start of the bytes when they are missing to locate the error
+func (r *bytesReader) next(n int64) ([]byte, error) {
+	if n > int64(len(r.data)-r.pos) {
+		return nil, r.end(ErrTruncated)
+	}
+	b := r.data[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b, nil
+}
+
+// String reader with nbt format
+func (r *bytesReader) String() (string, error) {
+	var b []byte
+	var err error
+
+	if b, err = r.next(2); err != nil {
+		return "", err
+	}
+	if b, err = r.next(int64(r.order.Uint16(b))); err != nil {
+		return "", err
+	}
+	if !r.mutf8 {
+		return string(b), nil
+	}
+	if str, ok := decodeMUTF8(b); ok {
+		return str, nil
+	}
+	if r.raw {
+		return string(b), nil
+	}
+	return "", errors.New(errorMUTF8)
+}
+
+// Byte reader with nbt format, it return io.EOF at the end of data
+func (r *bytesReader) Byte() (byte, error) {
+	if r.pos >= len(r.data) {
+		return 0, r.end(io.EOF)
+	}
+	r.pos++
+	return r.data[r.pos-1], nil
+}
+
+// Short reader with nbt format
+func (r *bytesReader) Short() (int16, error) {
+	var b []byte
+	var err error
+
+	if b, err = r.next(2); err != nil {
+		return 0, err
+	}
+	return int16(r.order.Uint16(b)), nil
+}
+
+// Int reader with nbt format
+func (r *bytesReader) Int() (int32, error) {
+	var b []byte
+	var err error
+
+	if b, err = r.next(4); err != nil {
+		return 0, err
+	}
+	return int32(r.order.Uint32(b)), nil
+}
+
+// Long reader with nbt format
+func (r *bytesReader) Long() (int64, error) {
+	var b []byte
+	var err error
+
+	if b, err = r.next(8); err != nil {
+		return 0, err
+	}
+	return int64(r.order.Uint64(b)), nil
+}
+
+// Float reader with nbt format
+func (r *bytesReader) Float() (float32, error) {
+	var b []byte
+	var err error
+
+	if b, err = r.next(4); err != nil {
+		return 0, err
+	}
+	return math.Float32frombits(r.order.Uint32(b)), nil
+}
+
+// Double reader with nbt format
+func (r *bytesReader) Double() (float64, error) {
+	var b []byte
+	var err error
+
+	if b, err = r.next(8); err != nil {
+		return 0, err
+	}
+	return math.Float64frombits(r.order.Uint64(b)), nil
+}
+
+// array return the data of an array with elements of size bytes
+func (r *bytesReader) array(size int64) ([]byte, int32, error) {
+	var n int32
+	var b []byte
+	var err error
+
+	if n, err = r.Int(); err != nil {
+		return nil, 0, err
+	}
+	if err = r.length(n); err != nil {
+		return nil, 0, err
+	}
+	if b, err = r.next(int64(n) * size); err != nil {
+		return nil, 0, err
+	}
+	return b, n, nil
+}
+
+// Bytes reader with nbt format
+func (r *bytesReader) Bytes() ([]byte, error) {
+	var b []byte
+	var err error
+
+	if b, _, err = r.array(1); err != nil {
+		return []byte{}, err
+	}
+	if r.alias {
+		return b[:len(b):len(b)], nil
+	}
+	ret := make([]byte, len(b))
+	copy(ret, b)
+	return ret, nil
+}
+
+// IntArray reader with nbt format
+func (r *bytesReader) IntArray() ([]int32, error) {
+	var b []byte
+	var n int32
+	var err error
+
+	if b, n, err = r.array(4); err != nil {
+		return []int32{}, err
+	}
+	ret := make([]int32, n)
+	for i := range ret {
+		ret[i] = int32(r.order.Uint32(b[i*4:]))
+	}
+	return ret, nil
+}
+
+// LongArray reader with nbt format
+func (r *bytesReader) LongArray() ([]int64, error) {
+	var b []byte
+	var n int32
+	var err error
+
+	if b, n, err = r.array(8); err != nil {
+		return []int64{}, err
+	}
+	ret := make([]int64, n)
+	for i := range ret {
+		ret[i] = int64(r.order.Uint64(b[i*8:]))
+	}
+	return ret, nil
+}
+
+// inflate return the decompressed data of a gzip or zlib payload. It return ErrMaxInflated
+// beyond the limit MaxInflated, and stop after the limit MaxBytes of a root tag
+func inflate(data []byte, compress string, limits Limits) ([]byte, error) {
+	var err error
+	var r io.ReadCloser
+	var buf bytes.Buffer
+
+	switch compress {
+	case CompressGZIP:
+		r, err = gzip.NewReader(bytes.NewReader(data))
+	case CompressZLIB:
+		r, err = zlib.NewReader(bytes.NewReader(data))
+	default:
+		return data, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var src io.Reader = &countReader{r: r, limit: limits.MaxInflated, err: ErrMaxInflated}
+	if limits.MaxBytes > 0 {
+		// one more byte to know that the data is cut
+		src = io.LimitReader(src, limits.MaxBytes+1)
+	}
+	buf.Grow(inflateHint(len(data), limits))
+	if _, err = buf.ReadFrom(src); err != nil {
+		return nil, err
+	}
+	if err = r.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// inflateHint return the size to allocate for the inflated data of n compressed bytes,
+// which is not larger than the limits
+func inflateHint(n int, limits Limits) int {
+	// the nbt are usually compressed 4 to 10 times
+	hint := int64(n) * 4
+	if limits.MaxInflated > 0 && hint > limits.MaxInflated {
+		hint = limits.MaxInflated
+	}
+	if limits.MaxBytes > 0 && hint > limits.MaxBytes+1 {
+		hint = limits.MaxBytes + 1
+	}
+	return int(hint)
+}
+
+// unmarshal the first root tag of data in place with the options
+func unmarshal(data []byte, order binary.ByteOrder, alias bool, opts UnmarshalOptions) (Tag, error) {
+	var err error
+	var t Tag
+
+	if data, err = inflate(data, detectCompression(data), opts.Limits); err != nil {
+		return nil, err
+	}
+	r := newBytesReader(data, order, opts.Limits)
+	r.alias = alias
+	r.raw = opts.RawStrings
+	if t, err = readRoot(r, false); err != nil {
+		if decodeErr, ok := err.(*DecodeError); ok {
+			decodeErr.Offset = int64(r.pos)
+		}
+		return nil, err
+	}
+	return t, nil
+}
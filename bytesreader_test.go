@@ -0,0 +1,192 @@
+package gonbt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newBenchChunk return a tag shaped like a chunk with its sections
+func newBenchChunk() *CompoundT {
+	sections := &ListT{ElemType: TagCompound}
+	for y := 0; y < 24; y++ {
+		section := &CompoundT{}
+		section.Set("Y", &ByteT{Value: byte(y)})
+		section.Set("BlockStates", &LongArrayT{Value: make([]int64, 256)})
+		section.Set("Biomes", &IntArrayT{Value: make([]int32, 64)})
+		section.Set("BlockLight", &ByteArrayT{Value: make([]byte, 2048)})
+		palette := &ListT{ElemType: TagCompound}
+		for i := 0; i < 8; i++ {
+			block := &CompoundT{}
+			block.Set("Name", &StringT{Value: "minecraft:block_" + strconv.Itoa(i)})
+			palette.Value = append(palette.Value, block)
+		}
+		section.Set("Palette", palette)
+		sections.Value = append(sections.Value, section)
+	}
+	chunk := &CompoundT{}
+	chunk.Set("xPos", &IntT{Value: 3})
+	chunk.Set("zPos", &IntT{Value: -7})
+	chunk.Set("Status", &StringT{Value: "minecraft:full"})
+	chunk.Set("sections", sections)
+	return chunk
+}
+
+func TestBytesReader(t *testing.T) {
+	t.Run("should return io.EOF because the data is empty", func(t *testing.T) {
+		_, err := NewBytesReader([]byte{}, binary.BigEndian).Byte()
+		assert.Equal(t, io.EOF, err)
+	})
+	t.Run("should return an error because the data is truncated", func(t *testing.T) {
+		r := NewBytesReader([]byte{0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x01}, binary.BigEndian)
+
+		ret, err := r.IntArray()
+		if assert.Error(t, err) {
+			assert.Equal(t, ErrTruncated, err)
+			assert.Empty(t, ret)
+		}
+		// the cursor is kept at the start of the missing values
+		assert.Equal(t, 4, r.(*bytesReader).pos)
+	})
+	t.Run("should return an error because the length is too large", func(t *testing.T) {
+		ret, err := NewBytesReader([]byte{0x7f, 0xff, 0xff, 0xff, 0x00}, binary.BigEndian).LongArray()
+		if assert.Error(t, err) {
+			assert.Equal(t, ErrTruncated, err)
+			assert.Empty(t, ret)
+		}
+	})
+	t.Run("should be ok with the primitives and the arrays", func(t *testing.T) {
+		r := NewBytesReader([]byte{
+			0x01, 0x02, 0x00, 0x02, 'i', 'd', 0x3f, 0xc0, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		}, binary.BigEndian)
+
+		short, err := r.Short()
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, 0x0102, short)
+		}
+		str, err := r.String()
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, "id", str)
+		}
+		f, err := r.Float()
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, float32(1.5), f)
+		}
+		longs, err := r.LongArray()
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, []int64{11, -1}, longs)
+		}
+	})
+}
+
+func TestInflateHint(t *testing.T) {
+	t.Run("should be ok and keep the size under the limits", func(t *testing.T) {
+		assert.Equal(t, 4<<20, inflateHint(1<<20, Limits{}))
+		assert.Equal(t, 1024, inflateHint(32<<20, Limits{MaxInflated: 1024}))
+		assert.Equal(t, 1025, inflateHint(32<<20, Limits{MaxBytes: 1024}))
+		assert.Equal(t, 1024, inflateHint(32<<20, Limits{MaxInflated: 1024, MaxBytes: 1024}))
+		assert.Equal(t, 400, inflateHint(100, Limits{MaxInflated: 1024, MaxBytes: 1024}))
+	})
+}
+
+func TestUnmarshalAlias(t *testing.T) {
+	t.Run("should be ok and share the memory of the data", func(t *testing.T) {
+		data, err := Marshal(&ByteArrayT{Name: "a", Value: []byte{1, 2, 3}}, CompressNone)
+		if assert.NoError(t, err) {
+			tag, err := UnmarshalAlias(data)
+			if assert.NoError(t, err) {
+				data[len(data)-1] = 42
+				assert.EqualValues(t, []byte{1, 2, 42}, tag.(*ByteArrayT).Value)
+				assert.EqualValues(t, 3, cap(tag.(*ByteArrayT).Value))
+			}
+		}
+	})
+	t.Run("should be ok and copy the data without alias", func(t *testing.T) {
+		data, err := Marshal(&ByteArrayT{Name: "a", Value: []byte{1, 2, 3}}, CompressNone)
+		if assert.NoError(t, err) {
+			tag, err := Unmarshal(data)
+			if assert.NoError(t, err) {
+				data[len(data)-1] = 42
+				assert.EqualValues(t, []byte{1, 2, 3}, tag.(*ByteArrayT).Value)
+			}
+		}
+	})
+	t.Run("should return a decode error with the offset", func(t *testing.T) {
+		data, err := Marshal(newBenchChunk(), CompressNone)
+		if assert.NoError(t, err) {
+			_, err = Unmarshal(data[:1000])
+			var decodeErr *DecodeError
+			if assert.True(t, errors.As(err, &decodeErr)) {
+				// the offset of the BlockStates values, which are cut
+				assert.EqualValues(t, 89, decodeErr.Offset)
+				assert.EqualValues(t, "sections[0].BlockStates", decodeErr.Path)
+				assert.True(t, errors.Is(err, ErrTruncated))
+			}
+		}
+	})
+	t.Run("should be ok with the same result as the stream reader", func(t *testing.T) {
+		data, err := Marshal(newBenchChunk(), CompressZLIB)
+		if assert.NoError(t, err) {
+			tag, err := UnmarshalAlias(data)
+			if assert.NoError(t, err) {
+				expected, err := NewDecoder(bytes.NewReader(data)).Decode()
+				if assert.NoError(t, err) {
+					assert.EqualValues(t, expected, tag)
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkReader(b *testing.B) {
+	data, err := Marshal(newBenchChunk(), CompressNone)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err = readRoot(NewReader(bytes.NewReader(data)), false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBytesReader(b *testing.B) {
+	data, err := Marshal(newBenchChunk(), CompressNone)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err = readRoot(NewBytesReader(data, binary.BigEndian), false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBytesReaderAlias(b *testing.B) {
+	data, err := Marshal(newBenchChunk(), CompressNone)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r := NewBytesReader(data, binary.BigEndian).(*bytesReader)
+		r.alias = true
+		if _, err = readRoot(r, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package gonbt
+
+// Clone return a deep copy of t, the elements of the lists and the compounds
+// which are not tags are shared
+func Clone(t Tag) Tag {
+	switch tag := t.(type) {
+	case *ByteT:
+		c := *tag
+		return &c
+	case *ShortT:
+		c := *tag
+		return &c
+	case *IntT:
+		c := *tag
+		return &c
+	case *LongT:
+		c := *tag
+		return &c
+	case *FloatT:
+		c := *tag
+		return &c
+	case *DoubleT:
+		c := *tag
+		return &c
+	case *StringT:
+		c := *tag
+		return &c
+	case *ByteArrayT:
+		c := &ByteArrayT{Name: tag.Name}
+		if tag.Value != nil {
+			c.Value = make([]byte, len(tag.Value))
+			copy(c.Value, tag.Value)
+		}
+		return c
+	case *IntArrayT:
+		c := &IntArrayT{Name: tag.Name}
+		if tag.Value != nil {
+			c.Value = make([]int32, len(tag.Value))
+			copy(c.Value, tag.Value)
+		}
+		return c
+	case *LongArrayT:
+		c := &LongArrayT{Name: tag.Name}
+		if tag.Value != nil {
+			c.Value = make([]int64, len(tag.Value))
+			copy(c.Value, tag.Value)
+		}
+		return c
+	case *ListT:
+		c := &ListT{Name: tag.Name, ElemType: tag.ElemType}
+		if tag.Value != nil {
+			c.Value = make([]interface{}, len(tag.Value))
+			for i, elem := range tag.Value {
+				c.Value[i] = cloneValue(elem)
+			}
+		}
+		return c
+	case *CompoundT:
+		c := &CompoundT{Name: tag.Name}
+		if tag.Value != nil {
+			c.Value = make(map[string]interface{}, len(tag.Value))
+			for name, elem := range tag.Value {
+				c.Value[name] = cloneValue(elem)
+			}
+		}
+		if tag.Order != nil {
+			c.Order = make([]string, len(tag.Order))
+			copy(c.Order, tag.Order)
+		}
+		return c
+	}
+	return t
+}
+
+// cloneValue return a deep copy of the element v of a list or a compound
+func cloneValue(v interface{}) interface{} {
+	if t, ok := v.(Tag); ok {
+		return Clone(t)
+	}
+	return v
+}
@@ -0,0 +1,44 @@
+package gonbt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClone(t *testing.T) {
+	t.Run("should be ok with a deep copy", func(t *testing.T) {
+		tag := newBenchChunk()
+
+		c := Clone(tag).(*CompoundT)
+		assert.Equal(t, tag, c)
+		c.SetInt("xPos", 10)
+		sections, _ := c.GetList("sections")
+		section, _ := sections.CompoundAt(0)
+		states, _ := section.GetLongArray("BlockStates")
+		states[0] = 7
+		section.Order[0] = "changed"
+
+		assert.True(t, Equal(tag, newBenchChunk()))
+		assert.False(t, Equal(tag, c))
+		assert.Equal(t, "Y", tag.Value["sections"].(*ListT).Value[0].(*CompoundT).Order[0])
+	})
+	t.Run("should be ok with all the tag types", func(t *testing.T) {
+		tags := []Tag{
+			&ByteT{Name: "a", Value: 1}, &ShortT{Value: 2}, &IntT{Value: 3}, &LongT{Value: 4},
+			&FloatT{Value: 5}, &DoubleT{Value: 6}, &StringT{Value: "7"}, &ByteArrayT{Value: []byte{8}},
+			&IntArrayT{Value: []int32{9}}, &LongArrayT{Value: []int64{10}}, &ListT{ElemType: TagInt},
+			&CompoundT{}, &ByteArrayT{},
+		}
+		for _, tag := range tags {
+			c := Clone(tag)
+			assert.Equal(t, tag, c)
+			assert.False(t, c == tag)
+		}
+	})
+	t.Run("should be ok and return the unknown tags as is", func(t *testing.T) {
+		tag := &fakeTag{}
+
+		assert.True(t, Clone(tag) == Tag(tag))
+	})
+}
@@ -0,0 +1,237 @@
+package gonbt
+
+import (
+	"sort"
+)
+
+// Keys return the names of the elements in the writing order: the names of Order
+// first, then the other elements sorted by name
+func (t *CompoundT) Keys() []string {
+	keys := make([]string, 0, len(t.Value))
+	seen := make(map[string]bool, len(t.Value))
+
+	for _, key := range t.Order {
+		if _, ok := t.Value[key]; !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	if len(keys) == len(t.Value) {
+		return keys
+	}
+
+	var others []string
+	for key := range t.Value {
+		if !seen[key] {
+			others = append(others, key)
+		}
+	}
+	sort.Strings(others)
+	return append(keys, others...)
+}
+
+// Get return the element name
+func (t *CompoundT) Get(name string) (Tag, bool) {
+	elem, ok := t.Value[name].(Tag)
+	return elem, ok
+}
+
+// Set the element name with the tag, a new element is added at the end of the order
+func (t *CompoundT) Set(name string, tag Tag) {
+	if t.Value == nil {
+		t.Value = make(map[string]interface{})
+	}
+	if _, ok := t.Value[name]; !ok {
+		t.Order = append(t.Order, name)
+	}
+	setName(tag, name)
+	t.Value[name] = tag
+}
+
+// Delete the element name
+func (t *CompoundT) Delete(name string) {
+	if _, ok := t.Value[name]; !ok {
+		return
+	}
+	delete(t.Value, name)
+	for i, key := range t.Order {
+		if key == name {
+			t.Order = append(t.Order[:i:i], t.Order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Sort the elements by name in the compound and its nested compounds,
+// to write a deterministic output whatever the order of the read data
+func (t *CompoundT) Sort() {
+	t.Order = nil
+	for _, value := range t.Value {
+		sortTag(value)
+	}
+}
+
+// sortTag sort the compounds nested in v
+func sortTag(v interface{}) {
+	switch tag := v.(type) {
+	case *CompoundT:
+		tag.Sort()
+	case *ListT:
+		for _, elem := range tag.Value {
+			sortTag(elem)
+		}
+	}
+}
+
+// GetByte return the value of the TAG_Byte name
+func (t *CompoundT) GetByte(name string) (byte, bool) {
+	elem, ok := t.Value[name].(*ByteT)
+	if !ok {
+		return 0, false
+	}
+	return elem.Value, true
+}
+
+// GetShort return the value of the TAG_Short name
+func (t *CompoundT) GetShort(name string) (int16, bool) {
+	elem, ok := t.Value[name].(*ShortT)
+	if !ok {
+		return 0, false
+	}
+	return elem.Value, true
+}
+
+// GetInt return the value of the TAG_Int name
+func (t *CompoundT) GetInt(name string) (int32, bool) {
+	elem, ok := t.Value[name].(*IntT)
+	if !ok {
+		return 0, false
+	}
+	return elem.Value, true
+}
+
+// GetLong return the value of the TAG_Long name
+func (t *CompoundT) GetLong(name string) (int64, bool) {
+	elem, ok := t.Value[name].(*LongT)
+	if !ok {
+		return 0, false
+	}
+	return elem.Value, true
+}
+
+// GetFloat return the value of the TAG_Float name
+func (t *CompoundT) GetFloat(name string) (float32, bool) {
+	elem, ok := t.Value[name].(*FloatT)
+	if !ok {
+		return 0, false
+	}
+	return elem.Value, true
+}
+
+// GetDouble return the value of the TAG_Double name
+func (t *CompoundT) GetDouble(name string) (float64, bool) {
+	elem, ok := t.Value[name].(*DoubleT)
+	if !ok {
+		return 0, false
+	}
+	return elem.Value, true
+}
+
+// GetByteArray return the value of the TAG_Byte_Array name
+func (t *CompoundT) GetByteArray(name string) ([]byte, bool) {
+	elem, ok := t.Value[name].(*ByteArrayT)
+	if !ok {
+		return nil, false
+	}
+	return elem.Value, true
+}
+
+// GetString return the value of the TAG_String name
+func (t *CompoundT) GetString(name string) (string, bool) {
+	elem, ok := t.Value[name].(*StringT)
+	if !ok {
+		return "", false
+	}
+	return elem.Value, true
+}
+
+// GetList return the TAG_List name
+func (t *CompoundT) GetList(name string) (*ListT, bool) {
+	elem, ok := t.Value[name].(*ListT)
+	return elem, ok
+}
+
+// GetCompound return the TAG_Compound name
+func (t *CompoundT) GetCompound(name string) (*CompoundT, bool) {
+	elem, ok := t.Value[name].(*CompoundT)
+	return elem, ok
+}
+
+// GetIntArray return the value of the TAG_Int_Array name
+func (t *CompoundT) GetIntArray(name string) ([]int32, bool) {
+	elem, ok := t.Value[name].(*IntArrayT)
+	if !ok {
+		return nil, false
+	}
+	return elem.Value, true
+}
+
+// GetLongArray return the value of the TAG_Long_Array name
+func (t *CompoundT) GetLongArray(name string) ([]int64, bool) {
+	elem, ok := t.Value[name].(*LongArrayT)
+	if !ok {
+		return nil, false
+	}
+	return elem.Value, true
+}
+
+// SetByte set the element name with a TAG_Byte
+func (t *CompoundT) SetByte(name string, value byte) {
+	t.Set(name, &ByteT{Value: value})
+}
+
+// SetShort set the element name with a TAG_Short
+func (t *CompoundT) SetShort(name string, value int16) {
+	t.Set(name, &ShortT{Value: value})
+}
+
+// SetInt set the element name with a TAG_Int
+func (t *CompoundT) SetInt(name string, value int32) {
+	t.Set(name, &IntT{Value: value})
+}
+
+// SetLong set the element name with a TAG_Long
+func (t *CompoundT) SetLong(name string, value int64) {
+	t.Set(name, &LongT{Value: value})
+}
+
+// SetFloat set the element name with a TAG_Float
+func (t *CompoundT) SetFloat(name string, value float32) {
+	t.Set(name, &FloatT{Value: value})
+}
+
+// SetDouble set the element name with a TAG_Double
+func (t *CompoundT) SetDouble(name string, value float64) {
+	t.Set(name, &DoubleT{Value: value})
+}
+
+// SetByteArray set the element name with a TAG_Byte_Array
+func (t *CompoundT) SetByteArray(name string, value []byte) {
+	t.Set(name, &ByteArrayT{Value: value})
+}
+
+// SetString set the element name with a TAG_String
+func (t *CompoundT) SetString(name string, value string) {
+	t.Set(name, &StringT{Value: value})
+}
+
+// SetIntArray set the element name with a TAG_Int_Array
+func (t *CompoundT) SetIntArray(name string, value []int32) {
+	t.Set(name, &IntArrayT{Value: value})
+}
+
+// SetLongArray set the element name with a TAG_Long_Array
+func (t *CompoundT) SetLongArray(name string, value []int64) {
+	t.Set(name, &LongArrayT{Value: value})
+}
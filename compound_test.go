@@ -0,0 +1,187 @@
+package gonbt
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const exampleServersPath = "./example/servers.dat"
+
+func TestCompoundT_Keys(t *testing.T) {
+	t.Run("should be ok with the keys sorted without order", func(t *testing.T) {
+		tag := &CompoundT{Value: map[string]interface{}{"b": &ByteT{}, "a": &ByteT{}, "c": &ByteT{}}}
+
+		assert.EqualValues(t, []string{"a", "b", "c"}, tag.Keys())
+	})
+	t.Run("should be ok with the order first and the missing keys sorted", func(t *testing.T) {
+		tag := &CompoundT{
+			Value: map[string]interface{}{"b": &ByteT{}, "a": &ByteT{}, "d": &ByteT{}, "c": &ByteT{}},
+			Order: []string{"c", "removed", "a", "c"},
+		}
+
+		assert.EqualValues(t, []string{"c", "a", "b", "d"}, tag.Keys())
+	})
+}
+
+func TestCompoundT_Set(t *testing.T) {
+	t.Run("should be ok with a new element and an existing element", func(t *testing.T) {
+		tag := &CompoundT{}
+		expectedTag := &CompoundT{
+			Value: map[string]interface{}{"z": &IntT{Name: "z", Value: 2}, "a": &StringT{Name: "a"}},
+			Order: []string{"z", "a"},
+		}
+
+		tag.Set("z", &IntT{Value: 1})
+		tag.Set("a", &StringT{Name: "wrong"})
+		tag.Set("z", &IntT{Value: 2})
+		assert.EqualValues(t, expectedTag, tag)
+		elem, ok := tag.Get("a")
+		if assert.True(t, ok) {
+			assert.EqualValues(t, &StringT{Name: "a"}, elem)
+		}
+	})
+}
+
+func TestCompoundT_Delete(t *testing.T) {
+	t.Run("should be ok", func(t *testing.T) {
+		order := []string{"a", "b", "c"}
+		tag := &CompoundT{Value: map[string]interface{}{"a": &ByteT{}, "b": &ByteT{}, "c": &ByteT{}}, Order: order}
+
+		tag.Delete("b")
+		tag.Delete("unknown")
+		assert.EqualValues(t, []string{"a", "c"}, tag.Keys())
+		assert.EqualValues(t, []string{"a", "b", "c"}, order)
+		_, ok := tag.Get("b")
+		assert.False(t, ok)
+	})
+}
+
+func TestCompoundT_Sort(t *testing.T) {
+	t.Run("should be ok with the nested compounds", func(t *testing.T) {
+		nested := &CompoundT{Value: map[string]interface{}{"y": &ByteT{}, "x": &ByteT{}}, Order: []string{"y", "x"}}
+		tag := &CompoundT{
+			Value: map[string]interface{}{"b": &ListT{Value: []interface{}{nested}}, "a": &ByteT{}},
+			Order: []string{"b", "a"},
+		}
+
+		tag.Sort()
+		assert.EqualValues(t, []string{"a", "b"}, tag.Keys())
+		assert.EqualValues(t, []string{"x", "y"}, nested.Keys())
+	})
+}
+
+func TestCompoundT_RoundTrip(t *testing.T) {
+	t.Run("should be ok with the same bytes than the read data", func(t *testing.T) {
+		data := []byte{TagCompound, 0x00, 0x04, 'r', 'o', 'o', 't',
+			TagByte, 0x00, 0x01, 'z', 0x01,
+			TagCompound, 0x00, 0x01, 'm',
+			TagShort, 0x00, 0x01, 'y', 0x00, 0x02,
+			TagShort, 0x00, 0x01, 'b', 0x00, 0x03,
+			TagEnd,
+			TagString, 0x00, 0x01, 'a', 0x00, 0x02, 'o', 'k',
+			TagEnd,
+		}
+
+		tag, err := Unmarshal(data)
+		if assert.NoError(t, err) {
+			out, err := Marshal(tag, CompressNone)
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, data, out)
+			}
+		}
+	})
+	t.Run("should be ok with the example file", func(t *testing.T) {
+		data, err := ioutil.ReadFile(exampleServersPath)
+		if assert.NoError(t, err) {
+			tag, err := Unmarshal(data)
+			if assert.NoError(t, err) {
+				out, err := Marshal(tag, CompressNone)
+				if assert.NoError(t, err) {
+					assert.True(t, bytes.Equal(data, out))
+				}
+			}
+		}
+	})
+}
+
+func TestCompoundT_Getters(t *testing.T) {
+	tag := &CompoundT{}
+	tag.SetByte("byte", 1)
+	tag.SetShort("short", 2)
+	tag.SetInt("int", 3)
+	tag.SetLong("long", 4)
+	tag.SetFloat("float", 5.5)
+	tag.SetDouble("double", 6.5)
+	tag.SetByteArray("bytes", []byte{7})
+	tag.SetString("string", "eight")
+	tag.SetIntArray("ints", []int32{9})
+	tag.SetLongArray("longs", []int64{10})
+	tag.Set("list", &ListT{ElemType: TagInt})
+	tag.Set("compound", &CompoundT{})
+
+	t.Run("should be ok with the setters", func(t *testing.T) {
+		assert.EqualValues(t, &IntT{Name: "int", Value: 3}, tag.Value["int"])
+		assert.EqualValues(t, &StringT{Name: "string", Value: "eight"}, tag.Value["string"])
+		assert.EqualValues(t, []string{"byte", "short", "int", "long", "float", "double",
+			"bytes", "string", "ints", "longs", "list", "compound"}, tag.Keys())
+	})
+	t.Run("should be ok with the getters", func(t *testing.T) {
+		b, ok := tag.GetByte("byte")
+		assert.True(t, ok)
+		assert.EqualValues(t, 1, b)
+		s, ok := tag.GetShort("short")
+		assert.True(t, ok)
+		assert.EqualValues(t, 2, s)
+		i, ok := tag.GetInt("int")
+		assert.True(t, ok)
+		assert.EqualValues(t, 3, i)
+		l, ok := tag.GetLong("long")
+		assert.True(t, ok)
+		assert.EqualValues(t, 4, l)
+		f, ok := tag.GetFloat("float")
+		assert.True(t, ok)
+		assert.EqualValues(t, 5.5, f)
+		d, ok := tag.GetDouble("double")
+		assert.True(t, ok)
+		assert.EqualValues(t, 6.5, d)
+		bytes, ok := tag.GetByteArray("bytes")
+		assert.True(t, ok)
+		assert.EqualValues(t, []byte{7}, bytes)
+		str, ok := tag.GetString("string")
+		assert.True(t, ok)
+		assert.EqualValues(t, "eight", str)
+		ints, ok := tag.GetIntArray("ints")
+		assert.True(t, ok)
+		assert.EqualValues(t, []int32{9}, ints)
+		longs, ok := tag.GetLongArray("longs")
+		assert.True(t, ok)
+		assert.EqualValues(t, []int64{10}, longs)
+		list, ok := tag.GetList("list")
+		assert.True(t, ok)
+		assert.EqualValues(t, TagInt, list.ElemType)
+		compound, ok := tag.GetCompound("compound")
+		assert.True(t, ok)
+		assert.NotNil(t, compound)
+	})
+	t.Run("should not be ok because the type doesn't match", func(t *testing.T) {
+		_, ok := tag.GetInt("short")
+		assert.False(t, ok)
+		_, ok = tag.GetString("int")
+		assert.False(t, ok)
+		_, ok = tag.GetCompound("list")
+		assert.False(t, ok)
+		_, ok = tag.GetList("compound")
+		assert.False(t, ok)
+		_, ok = tag.GetLongArray("ints")
+		assert.False(t, ok)
+	})
+	t.Run("should not be ok because the element is missing", func(t *testing.T) {
+		_, ok := tag.GetByte("missing")
+		assert.False(t, ok)
+		_, ok = (&CompoundT{}).GetCompound("missing")
+		assert.False(t, ok)
+	})
+}
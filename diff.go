@@ -0,0 +1,118 @@
+package gonbt
+
+// ChangeKind of the changes returned by Diff
+type ChangeKind int
+
+// change kinds
+const (
+	// ChangeAdded is a tag which is only in the new tree
+	ChangeAdded ChangeKind = iota + 1
+	// ChangeRemoved is a tag which is only in the old tree
+	ChangeRemoved
+	// ChangeChanged is a tag which has another type or value in the new tree
+	ChangeChanged
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeChanged:
+		return "changed"
+	}
+	return "unknown"
+}
+
+// Change between two tag trees
+type Change struct {
+	Kind ChangeKind
+	Path Path
+	// Old is the tag of the old tree, nil when it's added
+	Old Tag
+	// New is the tag of the new tree, nil when it's removed
+	New Tag
+}
+
+// String return the change with the snbt of the tags, like:
+// changed Inventory[0].Count: 64b -> 1b
+func (c Change) String() string {
+	msg := c.Kind.String()
+	if path := c.Path.String(); path != "" {
+		msg += " " + path
+	}
+	switch c.Kind {
+	case ChangeAdded:
+		return msg + ": " + compactSNBT(c.New)
+	case ChangeRemoved:
+		return msg + ": " + compactSNBT(c.Old)
+	}
+	return msg + ": " + compactSNBT(c.Old) + " -> " + compactSNBT(c.New)
+}
+
+// Diff return the changes from a to b, the compounds are compared by element
+// names and the lists by element indexes. The names of the roots are ignored
+func Diff(a, b Tag) []Change {
+	return diff(Path{}, a, b, nil)
+}
+
+// diff append to changes the changes from a to b at the path
+func diff(path Path, a, b Tag, changes []Change) []Change {
+	switch oldT := a.(type) {
+	case *CompoundT:
+		newT, ok := b.(*CompoundT)
+		if !ok {
+			break
+		}
+		for _, name := range oldT.Keys() {
+			oldElem, _ := oldT.Value[name].(Tag)
+			if newElem, ok := newT.Get(name); ok {
+				changes = diff(path.child(name), oldElem, newElem, changes)
+			} else {
+				changes = append(changes, Change{Kind: ChangeRemoved, Path: path.child(name), Old: oldElem})
+			}
+		}
+		for _, name := range newT.Keys() {
+			if _, ok := oldT.Value[name]; !ok {
+				newElem, _ := newT.Value[name].(Tag)
+				changes = append(changes, Change{Kind: ChangeAdded, Path: path.child(name), New: newElem})
+			}
+		}
+		return changes
+	case *ListT:
+		newT, ok := b.(*ListT)
+		if !ok {
+			break
+		}
+		oldType, oldErr := oldT.elemType()
+		newType, newErr := newT.elemType()
+		// the type of a list can change when it's empty before or after
+		if oldErr != nil || newErr != nil || oldType != newType && (len(oldT.Value) == 0) == (len(newT.Value) == 0) {
+			break
+		}
+		for i := 0; i < len(oldT.Value) || i < len(newT.Value); i++ {
+			var oldElem, newElem Tag
+
+			if i < len(oldT.Value) {
+				oldElem, _ = oldT.Value[i].(Tag)
+			}
+			if i < len(newT.Value) {
+				newElem, _ = newT.Value[i].(Tag)
+			}
+			switch {
+			case newElem == nil:
+				changes = append(changes, Change{Kind: ChangeRemoved, Path: path.index(i), Old: oldElem})
+			case oldElem == nil:
+				changes = append(changes, Change{Kind: ChangeAdded, Path: path.index(i), New: newElem})
+			default:
+				changes = diff(path.index(i), oldElem, newElem, changes)
+			}
+		}
+		return changes
+	}
+	if !equal(a, b, EqualOptions{}, false) {
+		changes = append(changes, Change{Kind: ChangeChanged, Path: path, Old: a, New: b})
+	}
+	return changes
+}
@@ -0,0 +1,68 @@
+package gonbt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// changeStrings return the changes as strings
+func changeStrings(changes []Change) []string {
+	var ret []string
+	for _, change := range changes {
+		ret = append(ret, change.String())
+	}
+	return ret
+}
+
+func TestDiff(t *testing.T) {
+	t.Run("should be ok without change", func(t *testing.T) {
+		assert.Empty(t, Diff(newPathTag(), newPathTag()))
+	})
+	t.Run("should be ok with the added, removed and changed tags", func(t *testing.T) {
+		a, _ := ParseSNBT(`{Name:"Steve",Pos:[1.0d,2.0d],Inventory:[{Slot:0b,Count:64b},{Slot:1b}],Old:1b,Type:1b}`)
+		b, _ := ParseSNBT(`{Name:"Alex",Pos:[1.0d,2.0d,3.0d],Inventory:[{Slot:0b,Count:1b,tag:{}}],Type:1s,New:[]}`)
+
+		changes := Diff(a, b)
+		assert.Equal(t, []string{
+			`changed Name: Steve -> Alex`,
+			`added Pos[2]: 3.0d`,
+			`changed Inventory[0].Count: 64b -> 1b`,
+			`added Inventory[0].tag: {}`,
+			`removed Inventory[1]: {Slot:1b}`,
+			`removed Old: 1b`,
+			`changed Type: 1b -> 1s`,
+			`added New: []`,
+		}, changeStrings(changes))
+		if assert.Len(t, changes, 8) {
+			assert.Equal(t, ChangeChanged, changes[2].Kind)
+			assert.Equal(t, "Inventory[0].Count", changes[2].Path.String())
+			assert.Equal(t, &ByteT{Name: "Count", Value: 64}, changes[2].Old)
+			assert.Equal(t, &ByteT{Name: "Count", Value: 1}, changes[2].New)
+			assert.Nil(t, changes[1].Old)
+			assert.Nil(t, changes[4].New)
+		}
+	})
+	t.Run("should be ok with the lists which change of element type", func(t *testing.T) {
+		a, _ := ParseSNBT(`{a:[1,2],b:[],c:[1]}`)
+		b, _ := ParseSNBT(`{a:["x"],b:[1],c:[]}`)
+
+		assert.Equal(t, []string{
+			`changed a: [1,2] -> [x]`,
+			`added b[0]: 1`,
+			`removed c[0]: 1`,
+		}, changeStrings(Diff(a, b)))
+		assert.Equal(t, []string{`changed: [] -> []`}, changeStrings(Diff(&ListT{ElemType: TagInt}, &ListT{})))
+	})
+	t.Run("should be ok with the roots and the arrays", func(t *testing.T) {
+		assert.Equal(t, []string{`changed: [I;1,2] -> [I;1,3]`},
+			changeStrings(Diff(&IntArrayT{Value: []int32{1, 2}}, &IntArrayT{Value: []int32{1, 3}})))
+		assert.Empty(t, Diff(&IntT{Name: "a", Value: 1}, &IntT{Name: "b", Value: 1}))
+	})
+	t.Run("should be ok with the change kinds as strings", func(t *testing.T) {
+		assert.Equal(t, "added", ChangeAdded.String())
+		assert.Equal(t, "removed", ChangeRemoved.String())
+		assert.Equal(t, "changed", ChangeChanged.String())
+		assert.Equal(t, "unknown", ChangeKind(0).String())
+	})
+}
@@ -0,0 +1,143 @@
+package gonbt
+
+import (
+	"bytes"
+	"math"
+)
+
+// EqualOptions to compare the tags with EqualWith
+type EqualOptions struct {
+	// FloatTolerance is the maximum difference between two equal floats or doubles
+	FloatTolerance float64
+	// UnorderedLists compare the elements of the lists whatever their order
+	UnorderedLists bool
+}
+
+// Equal report if a and b have the same types, names and values recursively.
+// The order of the compound elements is ignored, NaN values are equal
+func Equal(a, b Tag) bool {
+	return equal(a, b, EqualOptions{}, true)
+}
+
+// EqualWith report if a and b are equal like Equal with the options
+func EqualWith(a, b Tag, opts EqualOptions) bool {
+	return equal(a, b, opts, true)
+}
+
+// equal compare a and b, and their names if names is set
+func equal(a, b Tag, opts EqualOptions, names bool) bool {
+	switch a := a.(type) {
+	case *ByteT:
+		b, ok := b.(*ByteT)
+		return ok && (!names || a.Name == b.Name) && a.Value == b.Value
+	case *ShortT:
+		b, ok := b.(*ShortT)
+		return ok && (!names || a.Name == b.Name) && a.Value == b.Value
+	case *IntT:
+		b, ok := b.(*IntT)
+		return ok && (!names || a.Name == b.Name) && a.Value == b.Value
+	case *LongT:
+		b, ok := b.(*LongT)
+		return ok && (!names || a.Name == b.Name) && a.Value == b.Value
+	case *FloatT:
+		b, ok := b.(*FloatT)
+		return ok && (!names || a.Name == b.Name) && equalFloat(float64(a.Value), float64(b.Value), opts.FloatTolerance)
+	case *DoubleT:
+		b, ok := b.(*DoubleT)
+		return ok && (!names || a.Name == b.Name) && equalFloat(a.Value, b.Value, opts.FloatTolerance)
+	case *StringT:
+		b, ok := b.(*StringT)
+		return ok && (!names || a.Name == b.Name) && a.Value == b.Value
+	case *ByteArrayT:
+		b, ok := b.(*ByteArrayT)
+		return ok && (!names || a.Name == b.Name) && bytes.Equal(a.Value, b.Value)
+	case *IntArrayT:
+		b, ok := b.(*IntArrayT)
+		if !ok || names && a.Name != b.Name || len(a.Value) != len(b.Value) {
+			return false
+		}
+		for i := range a.Value {
+			if a.Value[i] != b.Value[i] {
+				return false
+			}
+		}
+		return true
+	case *LongArrayT:
+		b, ok := b.(*LongArrayT)
+		if !ok || names && a.Name != b.Name || len(a.Value) != len(b.Value) {
+			return false
+		}
+		for i := range a.Value {
+			if a.Value[i] != b.Value[i] {
+				return false
+			}
+		}
+		return true
+	case *ListT:
+		b, ok := b.(*ListT)
+		if !ok || names && a.Name != b.Name {
+			return false
+		}
+		return equalList(a, b, opts)
+	case *CompoundT:
+		b, ok := b.(*CompoundT)
+		if !ok || names && a.Name != b.Name || len(a.Value) != len(b.Value) {
+			return false
+		}
+		for name, v := range a.Value {
+			elemA, okA := v.(Tag)
+			elemB, okB := b.Value[name].(Tag)
+			if !okA || !okB || !equal(elemA, elemB, opts, true) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// equalFloat report if a and b are equal with the tolerance
+func equalFloat(a, b, tolerance float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.IsNaN(a) && math.IsNaN(b)
+	}
+	return a == b || math.Abs(a-b) <= tolerance
+}
+
+// equalList compare the element types and the elements of the lists
+func equalList(a, b *ListT, opts EqualOptions) bool {
+	typeA, errA := a.elemType()
+	typeB, errB := b.elemType()
+	if errA != nil || errB != nil || typeA != typeB || len(a.Value) != len(b.Value) {
+		return false
+	}
+	if !opts.UnorderedLists {
+		for i := range a.Value {
+			elemA, okA := a.Value[i].(Tag)
+			elemB, okB := b.Value[i].(Tag)
+			if !okA || !okB || !equal(elemA, elemB, opts, true) {
+				return false
+			}
+		}
+		return true
+	}
+
+	used := make([]bool, len(b.Value))
+	for _, v := range a.Value {
+		elemA, ok := v.(Tag)
+		if !ok {
+			return false
+		}
+		found := false
+		for j, w := range b.Value {
+			if elemB, ok := w.(Tag); ok && !used[j] && equal(elemA, elemB, opts, true) {
+				used[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package gonbt
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEqual(t *testing.T) {
+	t.Run("should be ok with the same trees", func(t *testing.T) {
+		a := newPathTag()
+		b := newPathTag()
+		b.(*CompoundT).Sort()
+
+		assert.True(t, Equal(a, b))
+		assert.True(t, Equal(newBenchChunk(), newBenchChunk()))
+		assert.True(t, Equal(&FloatT{Value: float32(math.NaN())}, &FloatT{Value: float32(math.NaN())}))
+	})
+	t.Run("should not be ok with different trees", func(t *testing.T) {
+		pairs := [][2]string{
+			{`{a:1b}`, `{a:1s}`},
+			{`{a:1b}`, `{a:2b}`},
+			{`{a:1b}`, `{a:1b,b:1b}`},
+			{`{a:1b}`, `{b:1b}`},
+			{`{a:[1,2]}`, `{a:[2,1]}`},
+			{`{a:[1,2]}`, `{a:[1]}`},
+			{`{a:[I;1,2]}`, `{a:[I;1,3]}`},
+			{`{a:[L;1L]}`, `{a:[L;1L,2L]}`},
+			{`{a:[B;1b]}`, `{a:[B;2b]}`},
+			{`{a:"x"}`, `{a:"y"}`},
+			{`{a:1.5f}`, `{a:1.6f}`},
+			{`{a:1.5d}`, `{a:1.5f}`},
+			{`{a:1L}`, `{a:2L}`},
+			{`{a:1}`, `{a:2}`},
+			{`{a:[]}`, `{a:{}}`},
+		}
+		for _, pair := range pairs {
+			a, _ := ParseSNBT(pair[0])
+			b, _ := ParseSNBT(pair[1])
+			assert.False(t, Equal(a, b), pair[0]+" "+pair[1])
+			assert.False(t, Equal(b, a), pair[1]+" "+pair[0])
+		}
+	})
+	t.Run("should not be ok with different names", func(t *testing.T) {
+		assert.False(t, Equal(&IntT{Name: "a"}, &IntT{Name: "b"}))
+		assert.False(t, Equal(&CompoundT{Name: "a"}, &CompoundT{Name: "b"}))
+	})
+	t.Run("should not be ok with different element types of empty lists", func(t *testing.T) {
+		assert.False(t, Equal(&ListT{ElemType: TagInt}, &ListT{ElemType: TagCompound}))
+		assert.True(t, Equal(&ListT{Value: []interface{}{&IntT{}}}, &ListT{ElemType: TagInt, Value: []interface{}{&IntT{}}}))
+	})
+	t.Run("should be ok with the float tolerance", func(t *testing.T) {
+		x := 0.1
+		a := &ListT{Value: []interface{}{&DoubleT{Value: x + 0.2}, &FloatT{Value: 1}}}
+		b := &ListT{Value: []interface{}{&DoubleT{Value: 0.3}, &FloatT{Value: 1.0001}}}
+
+		assert.False(t, Equal(a.Value[0].(Tag), b.Value[0].(Tag)))
+		assert.True(t, EqualWith(a.Value[0].(Tag), b.Value[0].(Tag), EqualOptions{FloatTolerance: 1e-9}))
+		assert.True(t, EqualWith(a.Value[1].(Tag), b.Value[1].(Tag), EqualOptions{FloatTolerance: 1e-3}))
+		assert.False(t, EqualWith(a.Value[1].(Tag), b.Value[1].(Tag), EqualOptions{FloatTolerance: 1e-5}))
+		assert.False(t, EqualWith(&DoubleT{Value: math.NaN()}, &DoubleT{}, EqualOptions{FloatTolerance: math.Inf(1)}))
+	})
+	t.Run("should be ok with the unordered lists", func(t *testing.T) {
+		a, _ := ParseSNBT(`{a:[{id:1},{id:2},{id:2}]}`)
+		b, _ := ParseSNBT(`{a:[{id:2},{id:1},{id:2}]}`)
+		c, _ := ParseSNBT(`{a:[{id:2},{id:1},{id:1}]}`)
+
+		assert.False(t, Equal(a, b))
+		assert.True(t, EqualWith(a, b, EqualOptions{UnorderedLists: true}))
+		assert.False(t, EqualWith(a, c, EqualOptions{UnorderedLists: true}))
+	})
+}
@@ -1,8 +1,212 @@
 package gonbt
 
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+)
+
 // errors list
 const (
-	errorEnd          = "End"
-	errorTag          = "tag not supported"
-	errorCompressType = "compression type unsupported"
+	errorEnd            = "End"
+	errorTag            = "tag not supported"
+	errorCompressType   = "compression type unsupported"
+	errorStreamStarted  = "the stream is already started"
+	errorStreamClosed   = "the stream is closed"
+	errorVarint         = "varint overflows"
+	errorNegativeLength = "negative length"
+	errorMUTF8          = "malformed modified UTF-8 string"
+	errorStringLength   = "string too long"
+	errorTruncated      = "unexpected end of data"
+	errorMaxDepth       = "maximum nesting depth exceeded"
+	errorMaxBytes       = "maximum decoded bytes exceeded"
+	errorMaxLength      = "maximum array or list length exceeded"
+	errorMaxInflated    = "maximum inflated size exceeded"
+	errorRootEnd        = "the root tag is a TAG_End"
+
+	errorUnmarshalTarget = "unmarshal target must be a non-nil pointer"
+	errorNilValue        = "nil value can't be converted to a tag"
+	errorUnsupportedType = "go type not supported"
+	errorMismatchType    = "tag type mismatch"
+	errorOverflow        = "value overflows the go type"
+	errorListType        = "list elements must have the same tag type"
+	errorListLength      = "list too long for the go array"
+	errorMapKey          = "map key must be a string"
+	errorEmbeddedPointer = "can't set the nil embedded pointer to an unexported struct"
+
+	errorSNBTValue       = "expected value"
+	errorSNBTKey         = "expected key"
+	errorSNBTCompoundEnd = "expected ',' or '}'"
+	errorSNBTListEnd     = "expected ',' or ']'"
+	errorSNBTString      = "unterminated string"
+	errorSNBTEscape      = "invalid escape sequence"
+	errorSNBTArrayType   = "invalid array type"
+	errorSNBTTrailing    = "trailing data after the value"
+	errorSNBTDepth       = "nesting too deep"
+	errorSNBTFloat       = "NaN and infinite values can't be written in snbt"
+
+	errorPathName   = "expected a name"
+	errorPathIndex  = "invalid list index"
+	errorPathFilter = "a compound filter must follow a name or start the path"
+	errorPathDot    = "expected '.' or '['"
+	errorNoMatch    = "no element matches the path"
+
+	errorSkipChildren = "skip the children of the tag"
+	errorSkipAll      = "skip all the remaining tags"
+
+	errorMerge           = "only the compounds can be merged"
+	errorPatchOp         = "unknown patch operation"
+	errorPatchValue      = "the patch operation has no value"
+	errorPatchAdd        = "the path of an add must end with a name, an index or []"
+	errorPatchMove       = "the source of a move must match one element"
+	errorPatchMoveTarget = "the path of a move must end with a name, an index or []"
+	errorPatchBefore     = "the element before is not in the compound"
+	errorPatchRoot       = "the root can't be removed or moved"
+)
+
+// sentinel errors to compare with errors.Is
+var (
+	// ErrUnknownTagType is returned for a tag type which is not supported
+	ErrUnknownTagType = errors.New(errorTag)
+	// ErrUnsupportedCompression is returned for a compression type which is not supported
+	ErrUnsupportedCompression = errors.New(errorCompressType)
+	// ErrTruncated is returned when the data ends in the middle of a tag
+	ErrTruncated = errors.New(errorTruncated)
+	// ErrMaxDepth is returned when the lists and compounds are nested beyond Limits.MaxDepth
+	ErrMaxDepth = errors.New(errorMaxDepth)
+	// ErrMaxBytes is returned when a root tag is larger than Limits.MaxBytes
+	ErrMaxBytes = errors.New(errorMaxBytes)
+	// ErrMaxLength is returned when an array or a list is longer than Limits.MaxLength
+	ErrMaxLength = errors.New(errorMaxLength)
+	// ErrMaxInflated is returned when the decompressed stream is larger than Limits.MaxInflated
+	ErrMaxInflated = errors.New(errorMaxInflated)
+	// ErrRootEnd is returned by the Tokenizer for a root TAG_End
+	ErrRootEnd = errors.New(errorRootEnd)
+	// ErrListType is returned when the elements of a list don't have the same tag type
+	ErrListType = errors.New(errorListType)
+	// ErrNoMatch is returned when no element matches a Path
+	ErrNoMatch = errors.New(errorNoMatch)
+	// SkipChildren is returned by a WalkFunc or a TransformFunc to skip the children of the tag
+	SkipChildren = errors.New(errorSkipChildren)
+	// SkipAll is returned by a WalkFunc or a TransformFunc to skip all the remaining tags
+	SkipAll = errors.New(errorSkipAll)
+)
+
+// operations to the TypeError
+const (
+	opMarshal   = "marshal"
+	opUnmarshal = "unmarshal"
 )
+
+// TypeError describe a go value which can't be converted from or to a Tag
+type TypeError struct {
+	// Op is the failed operation: marshal or unmarshal
+	Op string
+	// Path of the tag, like Data.Player.Inventory[3]
+	Path string
+	// Field is the go struct field, like Player.Inventory
+	Field string
+	// Type is the go type of the value
+	Type reflect.Type
+	// Tag is the tag type when it's known, TagEnd otherwise
+	Tag byte
+	// Msg describe the error
+	Msg string
+}
+
+func (e *TypeError) Error() string {
+	msg := e.Op
+	if e.Tag != TagEnd {
+		msg += " " + tagName(e.Tag) + " into"
+	}
+	if e.Type != nil {
+		msg += " go value of type " + e.Type.String()
+	}
+	if e.Field != "" {
+		msg += " (field " + e.Field + ")"
+	}
+	if e.Path != "" {
+		msg += " at " + e.Path
+	}
+	return msg + ": " + e.Msg
+}
+
+// SyntaxError describe an invalid stringified nbt
+type SyntaxError struct {
+	// Offset in bytes of the error
+	Offset int
+	// Line and Column of the error, starting at 1
+	Line   int
+	Column int
+	// Msg describe the error
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return "line " + strconv.Itoa(e.Line) + ", column " + strconv.Itoa(e.Column) + ": " + e.Msg
+}
+
+// PatchError describe an operation of a Patch which can't be applied
+type PatchError struct {
+	// Index of the operation in the patch
+	Index int
+	// Op and Path of the operation
+	Op   string
+	Path string
+	// Err is the cause of the error
+	Err error
+}
+
+func (e *PatchError) Error() string {
+	return "patch operation " + strconv.Itoa(e.Index) + " (" + e.Op + " " + e.Path + "): " + e.Err.Error()
+}
+
+// Unwrap return the cause of the error
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError describe an nbt data which can't be decoded
+type DecodeError struct {
+	// Offset in bytes of the error in the uncompressed data, -1 when it's unknown
+	Offset int64
+	// Path of the tag, like Data.Player.Inventory[3].tag
+	Path string
+	// Tag is the type of the tag which was decoding
+	Tag byte
+	// Err is the cause of the error
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	msg := "decode " + tagName(e.Tag)
+	if e.Path != "" {
+		msg += " at " + e.Path
+	}
+	if e.Offset >= 0 {
+		msg += ", offset " + strconv.FormatInt(e.Offset, 10)
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+// Unwrap return the cause of the error
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// decodeError add the segment of the tag path to err, seg is the name of the element
+// or its [index]. The first call wrap err in a DecodeError with the tag type tagT
+func decodeError(err error, seg string, tagT byte) error {
+	if decodeErr, ok := err.(*DecodeError); ok {
+		if seg != "" && decodeErr.Path != "" && decodeErr.Path[0] != '[' {
+			seg += "."
+		}
+		decodeErr.Path = seg + decodeErr.Path
+		return decodeErr
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = ErrTruncated
+	}
+	return &DecodeError{Offset: -1, Path: seg, Tag: tagT, Err: err}
+}
@@ -0,0 +1,86 @@
+package gonbt
+
+import (
+	"errors"
+)
+
+// DefaultMaxDepth is the nesting limit of the lists and compounds of minecraft
+const DefaultMaxDepth = 512
+
+// Limits of the decoder against the hostile data, a zero value disables the limit
+type Limits struct {
+	// MaxDepth of the nested lists and compounds
+	MaxDepth int
+	// MaxBytes decoded by a root tag
+	MaxBytes int64
+	// MaxLength of the arrays and the lists
+	MaxLength int
+	// MaxInflated size of the decompressed gzip or zlib stream
+	MaxInflated int64
+}
+
+// DefaultLimits return the limits set by NewDecoder and the readers
+func DefaultLimits() Limits {
+	return Limits{MaxDepth: DefaultMaxDepth}
+}
+
+// limitedReader is implemented by the readers which check the Limits of the nested tags
+type limitedReader interface {
+	enter() error
+	leave()
+	length(n int32) error
+}
+
+// limiter check the depth and the length limits, its zero value has no limit
+type limiter struct {
+	limits Limits
+	depth  int
+}
+
+// enter a list or a compound, the depth is unchanged when the limit is exceeded
+func (l *limiter) enter() error {
+	if l.limits.MaxDepth > 0 && l.depth >= l.limits.MaxDepth {
+		return ErrMaxDepth
+	}
+	l.depth++
+	return nil
+}
+
+// leave a list or a compound
+func (l *limiter) leave() {
+	l.depth--
+}
+
+// length check the length of an array or a list
+func (l *limiter) length(n int32) error {
+	if n < 0 {
+		return errors.New(errorNegativeLength)
+	}
+	if l.limits.MaxLength > 0 && int64(n) > int64(l.limits.MaxLength) {
+		return ErrMaxLength
+	}
+	return nil
+}
+
+// enter the nested tag when the reader check the limits
+func enter(reader Reader) error {
+	if l, ok := reader.(limitedReader); ok {
+		return l.enter()
+	}
+	return nil
+}
+
+// leave the nested tag when the reader check the limits
+func leave(reader Reader) {
+	if l, ok := reader.(limitedReader); ok {
+		l.leave()
+	}
+}
+
+// checkLength of a list when the reader check the limits
+func checkLength(reader Reader, n int32) error {
+	if l, ok := reader.(limitedReader); ok {
+		return l.length(n)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package gonbt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nestedLists return the data of a root list with depth nested lists
+func nestedLists(depth int) []byte {
+	data := []byte{TagList, 0x00, 0x00}
+	for i := 1; i < depth; i++ {
+		data = append(data, TagList, 0x00, 0x00, 0x00, 0x01)
+	}
+	return append(data, TagEnd, 0x00, 0x00, 0x00, 0x00)
+}
+
+func TestLimits(t *testing.T) {
+	t.Run("should return an error because the depth exceeds the default limit", func(t *testing.T) {
+		_, err := NewDecoder(bytes.NewReader(nestedLists(DefaultMaxDepth + 1))).Decode()
+		var decodeErr *DecodeError
+		if assert.True(t, errors.As(err, &decodeErr)) {
+			assert.True(t, errors.Is(err, ErrMaxDepth))
+			assert.EqualValues(t, TagList, decodeErr.Tag)
+		}
+	})
+	t.Run("should be ok with the depth of a reader reused after the depth limit", func(t *testing.T) {
+		r := NewBytesReader(nil, binary.BigEndian).(*bytesReader)
+		r.limits.MaxDepth = 1
+
+		assert.NoError(t, r.enter())
+		assert.Equal(t, ErrMaxDepth, r.enter())
+		r.leave()
+		assert.Equal(t, 0, r.depth)
+		assert.NoError(t, r.enter())
+	})
+	t.Run("should be ok with the depth of the default limit", func(t *testing.T) {
+		_, err := NewDecoder(bytes.NewReader(nestedLists(DefaultMaxDepth))).Decode()
+		assert.NoError(t, err)
+	})
+	t.Run("should be ok without depth limit", func(t *testing.T) {
+		decoder := NewDecoder(bytes.NewReader(nestedLists(DefaultMaxDepth + 1)))
+		assert.NoError(t, decoder.SetLimits(Limits{}))
+		_, err := decoder.Decode()
+		assert.NoError(t, err)
+	})
+	t.Run("should return an error because the array is too long", func(t *testing.T) {
+		data := []byte{TagIntArray, 0x00, 0x01, 'a', 0x7f, 0xff, 0xff, 0xff}
+
+		decoder := NewDecoder(bytes.NewReader(data))
+		assert.NoError(t, decoder.SetLimits(Limits{MaxLength: 1024}))
+		_, err := decoder.Decode()
+		if assert.Error(t, err) {
+			assert.True(t, errors.Is(err, ErrMaxLength))
+		}
+	})
+	t.Run("should return an error because the list is too long", func(t *testing.T) {
+		data := []byte{TagCompound, 0x00, 0x00, TagList, 0x00, 0x01, 'l', TagByte, 0x00, 0x00, 0x04, 0x01}
+
+		decoder := NewDecoder(bytes.NewReader(data))
+		assert.NoError(t, decoder.SetLimits(Limits{MaxLength: 1024}))
+		_, err := decoder.Decode()
+		var decodeErr *DecodeError
+		if assert.True(t, errors.As(err, &decodeErr)) {
+			assert.True(t, errors.Is(err, ErrMaxLength))
+			assert.EqualValues(t, "l", decodeErr.Path)
+		}
+	})
+	t.Run("should return an error because the length is negative", func(t *testing.T) {
+		data := []byte{TagByteArray, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff}
+
+		_, err := NewDecoder(bytes.NewReader(data)).Decode()
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), errorNegativeLength)
+		}
+	})
+	t.Run("should return an error because the root tag has too many bytes", func(t *testing.T) {
+		data, err := Marshal(newTestTag(), CompressNone)
+		if assert.NoError(t, err) {
+			stream := append(append([]byte{}, data...), data...)
+			decoder := NewDecoder(bytes.NewReader(stream))
+			assert.NoError(t, decoder.SetLimits(Limits{MaxBytes: int64(len(data))}))
+			_, err = decoder.Decode()
+			assert.NoError(t, err)
+			_, err = decoder.Decode()
+			assert.NoError(t, err)
+
+			decoder = NewDecoder(bytes.NewReader(data))
+			assert.NoError(t, decoder.SetLimits(Limits{MaxBytes: int64(len(data) - 1)}))
+			_, err = decoder.Decode()
+			assert.True(t, errors.Is(err, ErrMaxBytes))
+		}
+	})
+	t.Run("should return an error because the inflated data is too large", func(t *testing.T) {
+		tag := &ByteArrayT{Value: make([]byte, 1<<20)}
+		data, err := Marshal(tag, CompressGZIP)
+		if assert.NoError(t, err) {
+			decoder := NewDecoder(bytes.NewReader(data))
+			assert.NoError(t, decoder.SetLimits(Limits{MaxInflated: 1 << 16}))
+			_, err = decoder.Decode()
+			assert.True(t, errors.Is(err, ErrMaxInflated))
+		}
+	})
+	t.Run("should return an error because the depth exceeds the limit of the varint reader", func(t *testing.T) {
+		var buf bytes.Buffer
+		tag := &ListT{Value: []interface{}{&ListT{Value: []interface{}{&ListT{}}}}}
+
+		encoder := NewEncoder(&buf)
+		assert.NoError(t, encoder.SetVarint(true))
+		if assert.NoError(t, encoder.Encode(tag)) {
+			decoder := NewDecoder(&buf)
+			assert.NoError(t, decoder.SetVarint(true))
+			assert.NoError(t, decoder.SetLimits(Limits{MaxDepth: 2}))
+			_, err := decoder.Decode()
+			assert.True(t, errors.Is(err, ErrMaxDepth))
+		}
+	})
+}
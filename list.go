@@ -0,0 +1,64 @@
+package gonbt
+
+// Len return the number of elements
+func (t *ListT) Len() int {
+	return len(t.Value)
+}
+
+// At return the element i
+func (t *ListT) At(i int) (Tag, bool) {
+	if i < 0 || i >= len(t.Value) {
+		return nil, false
+	}
+	elem, ok := t.Value[i].(Tag)
+	return elem, ok
+}
+
+// CompoundAt return the TAG_Compound element i
+func (t *ListT) CompoundAt(i int) (*CompoundT, bool) {
+	elem, ok := t.At(i)
+	if !ok {
+		return nil, false
+	}
+	compound, ok := elem.(*CompoundT)
+	return compound, ok
+}
+
+// ListAt return the TAG_List element i
+func (t *ListT) ListAt(i int) (*ListT, bool) {
+	elem, ok := t.At(i)
+	if !ok {
+		return nil, false
+	}
+	list, ok := elem.(*ListT)
+	return list, ok
+}
+
+// Append the elements at the end of the list, they must have the element type
+// of the list. The element type of an empty list without type is set by the first element
+func (t *ListT) Append(elems ...Tag) error {
+	var err error
+	var tagT byte
+
+	if tagT, err = t.elemType(); err != nil {
+		return err
+	}
+	for _, elem := range elems {
+		var elemT byte
+
+		if elemT, err = TagType(elem); err != nil {
+			return err
+		}
+		if tagT == TagEnd && len(t.Value) == 0 {
+			tagT = elemT
+		} else if elemT != tagT {
+			return ErrListType
+		}
+	}
+	for _, elem := range elems {
+		setName(elem, "")
+		t.Value = append(t.Value, elem)
+	}
+	t.ElemType = tagT
+	return nil
+}
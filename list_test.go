@@ -0,0 +1,73 @@
+package gonbt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListT_At(t *testing.T) {
+	item := &CompoundT{}
+	item.SetString("id", "minecraft:stone")
+	tag := &ListT{ElemType: TagCompound, Value: []interface{}{item}}
+
+	t.Run("should be ok with an element", func(t *testing.T) {
+		assert.Equal(t, 1, tag.Len())
+		elem, ok := tag.At(0)
+		if assert.True(t, ok) {
+			assert.Equal(t, item, elem)
+		}
+		compound, ok := tag.CompoundAt(0)
+		if assert.True(t, ok) {
+			assert.Equal(t, item, compound)
+		}
+	})
+	t.Run("should not be ok because the index is out of range", func(t *testing.T) {
+		_, ok := tag.At(1)
+		assert.False(t, ok)
+		_, ok = tag.At(-1)
+		assert.False(t, ok)
+		_, ok = tag.CompoundAt(1)
+		assert.False(t, ok)
+	})
+	t.Run("should not be ok because the type doesn't match", func(t *testing.T) {
+		_, ok := tag.ListAt(0)
+		assert.False(t, ok)
+	})
+}
+
+func TestListT_Append(t *testing.T) {
+	t.Run("should be ok and set the element type of an empty list", func(t *testing.T) {
+		tag := &ListT{}
+
+		err := tag.Append(&ListT{Name: "a"}, &ListT{ElemType: TagInt})
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, TagList, tag.ElemType)
+			assert.Equal(t, 2, tag.Len())
+			list, ok := tag.ListAt(0)
+			if assert.True(t, ok) {
+				assert.Empty(t, list.Name)
+			}
+		}
+	})
+	t.Run("should be ok with the element type of the list", func(t *testing.T) {
+		tag := &ListT{ElemType: TagInt}
+
+		assert.NoError(t, tag.Append(&IntT{Value: 1}))
+		assert.NoError(t, tag.Append(&IntT{Value: 2}))
+		assert.Equal(t, 2, tag.Len())
+	})
+	t.Run("should return an error because an element doesn't match the element type", func(t *testing.T) {
+		tag := &ListT{ElemType: TagInt}
+
+		err := tag.Append(&IntT{Value: 1}, &StringT{Value: "a"})
+		assert.Equal(t, ErrListType, err)
+		assert.Equal(t, 0, tag.Len())
+	})
+	t.Run("should return an error because the tag is not supported", func(t *testing.T) {
+		tag := &ListT{}
+
+		err := tag.Append(&fakeTag{})
+		assert.Equal(t, ErrUnknownTagType, err)
+	})
+}
@@ -0,0 +1,31 @@
+package gonbt
+
+import "errors"
+
+// Merge src in dst like the /data merge command: the compounds of both are merged
+// recursively, the other elements of dst are replaced by a copy of the src elements
+func Merge(dst, src Tag) error {
+	dstT, okDst := dst.(*CompoundT)
+	srcT, okSrc := src.(*CompoundT)
+	if !okDst || !okSrc {
+		return errors.New(errorMerge)
+	}
+	mergeCompound(dstT, srcT)
+	return nil
+}
+
+func mergeCompound(dst, src *CompoundT) {
+	for _, name := range src.Keys() {
+		elem, ok := src.Value[name].(Tag)
+		if !ok {
+			continue
+		}
+		if srcT, ok := elem.(*CompoundT); ok {
+			if dstT, ok := dst.Value[name].(*CompoundT); ok {
+				mergeCompound(dstT, srcT)
+				continue
+			}
+		}
+		dst.Set(name, Clone(elem))
+	}
+}
@@ -0,0 +1,37 @@
+package gonbt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	t.Run("should be ok and merge the compounds recursively", func(t *testing.T) {
+		dst, _ := ParseSNBT(`{Health:20.0f,Pos:[1.0d,2.0d],tag:{display:{Name:Rock,Color:1},Damage:0}}`)
+		src, _ := ParseSNBT(`{Health:10.0f,Pos:[3.0d],tag:{display:{Name:Stone},Unbreakable:1b},New:{a:1}}`)
+		expected, _ := ParseSNBT(`{Health:10.0f,Pos:[3.0d],tag:{display:{Name:Stone,Color:1},Damage:0,Unbreakable:1b},New:{a:1}}`)
+
+		if assert.NoError(t, Merge(dst, src)) {
+			assert.True(t, Equal(expected, dst), compactSNBT(dst))
+			assert.Equal(t, []string{"Health", "Pos", "tag", "New"}, dst.(*CompoundT).Keys())
+			// the elements of src are copied
+			newT, _ := dst.(*CompoundT).GetCompound("New")
+			newT.SetInt("a", 2)
+			a, _ := MustParsePath(`New.a`).Get(src)
+			assert.Equal(t, []Tag{&IntT{Name: "a", Value: 1}}, a)
+		}
+	})
+	t.Run("should be ok and replace a tag by a compound", func(t *testing.T) {
+		dst, _ := ParseSNBT(`{a:1}`)
+		src, _ := ParseSNBT(`{a:{b:1}}`)
+
+		if assert.NoError(t, Merge(dst, src)) {
+			assert.True(t, Equal(src, dst))
+		}
+	})
+	t.Run("should return an error because the tags are not compounds", func(t *testing.T) {
+		assert.EqualError(t, Merge(&ListT{}, &CompoundT{}), errorMerge)
+		assert.EqualError(t, Merge(&CompoundT{}, &IntT{}), errorMerge)
+	})
+}
@@ -0,0 +1,110 @@
+package gonbt
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// java write the strings with the modified UTF-8: the NUL character is encoded on
+// two bytes 0xC0 0x80 and the supplementary characters are encoded with the
+// 3-byte sequences of their UTF-16 surrogate pair
+
+// decodeMUTF8 return the go string of the modified UTF-8 data, the standard
+// 4-byte sequences are accepted too. It return false when data is malformed
+func decodeMUTF8(data []byte) (string, bool) {
+	var i int
+
+	for i < len(data) && data[i] < 0x80 {
+		i++
+	}
+	if i == len(data) {
+		return string(data), true
+	}
+	out := make([]byte, i, len(data))
+	copy(out, data)
+	for i < len(data) {
+		c := data[i]
+		if c < 0x80 {
+			out = append(out, c)
+			i++
+			continue
+		}
+		if c == 0xc0 && i+1 < len(data) && data[i+1] == 0x80 {
+			out = append(out, 0x00)
+			i += 2
+			continue
+		}
+		if r, size := utf8.DecodeRune(data[i:]); r != utf8.RuneError || size > 1 {
+			out = append(out, data[i:i+size]...)
+			i += size
+			continue
+		}
+		// surrogate pair, rejected by the go decoder
+		high, ok := surrogate(data[i:])
+		if !ok || high >= 0xdc00 {
+			return "", false
+		}
+		low, ok := surrogate(data[i+3:])
+		if !ok || low < 0xdc00 {
+			return "", false
+		}
+		var buf [utf8.UTFMax]byte
+		n := utf8.EncodeRune(buf[:], utf16.DecodeRune(high, low))
+		out = append(out, buf[:n]...)
+		i += 6
+	}
+	return string(out), true
+}
+
+// surrogate return the UTF-16 surrogate encoded on the 3 first bytes of data
+func surrogate(data []byte) (rune, bool) {
+	if len(data) < 3 || data[0] != 0xed || data[1] < 0xa0 || data[1] > 0xbf || data[2]&0xc0 != 0x80 {
+		return 0, false
+	}
+	return 0xd000 | rune(data[1]&0x3f)<<6 | rune(data[2]&0x3f), true
+}
+
+// plainMUTF8 return the length of the prefix of str which has the same bytes
+// in UTF-8 and in modified UTF-8
+func plainMUTF8(str string) int {
+	var i int
+
+	for i < len(str) && str[i] != 0x00 && str[i] < 0xf0 {
+		i++
+	}
+	return i
+}
+
+// encodeMUTF8 return the modified UTF-8 data of the go string str
+func encodeMUTF8(str string) []byte {
+	i := plainMUTF8(str)
+	if i == len(str) {
+		return []byte(str)
+	}
+	out := make([]byte, i, len(str)+2)
+	copy(out, str)
+	for i < len(str) {
+		c := str[i]
+		if c == 0x00 {
+			out = append(out, 0xc0, 0x80)
+			i++
+			continue
+		}
+		if c >= 0xf0 {
+			if r, size := utf8.DecodeRuneInString(str[i:]); size == 4 {
+				high, low := utf16.EncodeRune(r)
+				out = appendSurrogate(appendSurrogate(out, high), low)
+				i += size
+				continue
+			}
+		}
+		out = append(out, c)
+		i++
+	}
+	return out
+}
+
+// appendSurrogate append the 3-byte sequence of the UTF-16 surrogate r
+func appendSurrogate(out []byte, r rune) []byte {
+	return append(out, 0xe0|byte(r>>12), 0x80|byte(r>>6)&0x3f, 0x80|byte(r)&0x3f)
+}
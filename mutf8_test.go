@@ -0,0 +1,54 @@
+package gonbt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeMUTF8(t *testing.T) {
+	t.Run("should return false because the surrogate is not paired", func(t *testing.T) {
+		_, ok := decodeMUTF8([]byte{'a', 0xed, 0xa0, 0xbd})
+		assert.False(t, ok)
+		_, ok = decodeMUTF8([]byte{0xed, 0xb8, 0x80, 0xed, 0xa0, 0xbd})
+		assert.False(t, ok)
+	})
+	t.Run("should return false because the sequence is invalid", func(t *testing.T) {
+		_, ok := decodeMUTF8([]byte{'a', 0xff})
+		assert.False(t, ok)
+		_, ok = decodeMUTF8([]byte{0xc0})
+		assert.False(t, ok)
+	})
+	t.Run("should be ok with the NUL and the supplementary characters", func(t *testing.T) {
+		str, ok := decodeMUTF8([]byte{'a', 0xc0, 0x80, 'b', 0xed, 0xa0, 0xbd, 0xed, 0xb8, 0x80, 0xc3, 0xa9})
+		if assert.True(t, ok) {
+			assert.EqualValues(t, "a\x00b😀é", str)
+		}
+	})
+	t.Run("should be ok with the standard 4-byte sequences", func(t *testing.T) {
+		str, ok := decodeMUTF8([]byte("sign 😀"))
+		if assert.True(t, ok) {
+			assert.EqualValues(t, "sign 😀", str)
+		}
+	})
+}
+
+func TestEncodeMUTF8(t *testing.T) {
+	t.Run("should be ok with the plain strings", func(t *testing.T) {
+		assert.EqualValues(t, []byte("minecraft:stone é"), encodeMUTF8("minecraft:stone é"))
+		assert.EqualValues(t, []byte{}, encodeMUTF8(""))
+	})
+	t.Run("should be ok with the NUL and the supplementary characters", func(t *testing.T) {
+		expectedData := []byte{'a', 0xc0, 0x80, 'b', 0xed, 0xa0, 0xbd, 0xed, 0xb8, 0x80, 0xc3, 0xa9}
+
+		assert.EqualValues(t, expectedData, encodeMUTF8("a\x00b😀é"))
+	})
+	t.Run("should be ok with a round trip", func(t *testing.T) {
+		for _, str := range []string{"", "book page 📖 with \x00 and 𝄞", "\U0010ffff", "￿"} {
+			decoded, ok := decodeMUTF8(encodeMUTF8(str))
+			if assert.True(t, ok) {
+				assert.EqualValues(t, str, decoded)
+			}
+		}
+	})
+}
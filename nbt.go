@@ -2,11 +2,11 @@ package gonbt
 
 import (
 	"bytes"
+	"compress/flate"
 	"compress/gzip"
-	"errors"
+	"compress/zlib"
+	"encoding/binary"
 	"io"
-	"io/ioutil"
-	"net/http"
 )
 
 // constant compression type
@@ -16,85 +16,110 @@ const (
 	CompressNone = "none"
 )
 
+// compression levels to MarshalLevel, shared by gzip and zlib
+const (
+	NoCompression      = flate.NoCompression
+	BestSpeed          = flate.BestSpeed
+	BestCompression    = flate.BestCompression
+	DefaultCompression = flate.DefaultCompression
+)
+
 // read and write data with the RFC NBT describe here:
 // https://minecraft.gamepedia.com/NBT_format
 
-// Unmarshal data
-func Unmarshal(data []byte) (Tag, error) {
-	var err error
-	var reader Reader
-	var tagT byte
-	var name string
-	var t Tag
-	var driver io.Reader
-
-	driver = bytes.NewReader(data)
-	contentType := http.DetectContentType(data)
-	if contentType == "application/x-gzip" {
-		var gReader *gzip.Reader
-		if gReader, err = gzip.NewReader(driver); err != nil {
-			return nil, err
-		}
-		defer gReader.Close()
-
-		if data, err = ioutil.ReadAll(gReader); err != nil {
-			return nil, err
-		}
-		driver = bytes.NewBuffer(data)
+// detectCompression return the compression type of data from its header
+func detectCompression(data []byte) string {
+	if len(data) < 2 {
+		return CompressNone
 	}
-
-	reader = NewReader(driver)
-	if tagT, err = reader.Byte(); err != nil {
-		return nil, err
+	// gzip magic number
+	if data[0] == 0x1f && data[1] == 0x8b {
+		return CompressGZIP
 	}
-	if name, err = reader.String(); err != nil {
-		return nil, err
+	// zlib header: deflate method with a 32K window (0x78) and the check bits,
+	// 0x78 0x01, 0x78 0x5e, 0x78 0x9c or 0x78 0xda
+	if data[0] == 0x78 && (uint16(data[0])<<8|uint16(data[1]))%31 == 0 {
+		return CompressZLIB
 	}
+	return CompressNone
+}
 
-	if t, err = NewTag(tagT, name); err != nil && err.Error() != errorEnd {
-		return nil, err
-	}
-	if err == nil {
-		if err = t.Read(reader); err != nil {
-			return nil, err
-		}
-	}
+// Unmarshal data, the gzip and zlib compressions are detected
+func Unmarshal(data []byte) (Tag, error) {
+	return unmarshal(data, binary.BigEndian, false, UnmarshalOptions{Limits: DefaultLimits()})
+}
+
+// UnmarshalAlias data like Unmarshal, but the ByteArrayT values share the memory of
+// the uncompressed data rather than copy it. data must not be modified while the tag is used
+func UnmarshalAlias(data []byte) (Tag, error) {
+	return unmarshal(data, binary.BigEndian, true, UnmarshalOptions{Limits: DefaultLimits()})
+}
+
+// UnmarshalLE data with the little-endian byte order of the bedrock edition
+func UnmarshalLE(data []byte) (Tag, error) {
+	return unmarshal(data, binary.LittleEndian, false, UnmarshalOptions{Limits: DefaultLimits()})
+}
+
+// UnmarshalOptions to decode the data with UnmarshalWith
+type UnmarshalOptions struct {
+	// Limits of the decoding against the hostile data, like the files uploaded by
+	// the players. Unmarshal use DefaultLimits, the zero value has no limit.
+	// The compressed data are inflated in memory up to MaxInflated
+	Limits Limits
+	// RawStrings keep the bytes of the strings which are not valid modified UTF-8
+	// rather than return an error
+	RawStrings bool
+}
 
-	return t, nil
+// UnmarshalWith data like Unmarshal with the options
+func UnmarshalWith(data []byte, opts UnmarshalOptions) (Tag, error) {
+	return unmarshal(data, binary.BigEndian, false, opts)
 }
 
-// Marshal data
+// Marshal data with the compression type and the level BestSpeed
 func Marshal(t Tag, compress string) ([]byte, error) {
-	var err error
-	var driver io.Writer
-	var buf *bytes.Buffer
-	var writer Writer
-	var output []byte
+	return MarshalLevel(t, compress, BestSpeed)
+}
+
+// MarshalLevel marshal data with the compression type and level
+func MarshalLevel(t Tag, compress string, level int) ([]byte, error) {
+	return marshal(t, compress, level, binary.BigEndian)
+}
+
+// MarshalLE marshal data with the little-endian byte order of the bedrock edition
+func MarshalLE(t Tag, compress string) ([]byte, error) {
+	return marshal(t, compress, BestSpeed, binary.LittleEndian)
+}
 
-	buf = bytes.NewBuffer([]byte{})
-	driver = buf
+// marshal data with the compression type, level and the byte order
+func marshal(t Tag, compress string, level int, order binary.ByteOrder) ([]byte, error) {
+	var err error
+	var buf bytes.Buffer
 
-	writer = NewWriter(driver)
-	if err = t.Write(writer, true); err != nil {
+	encoder := NewEncoder(&buf)
+	if err = encoder.SetCompression(compress, level); err != nil {
 		return []byte{}, err
 	}
+	if err = encoder.SetByteOrder(order); err != nil {
+		return []byte{}, err
+	}
+	if err = encoder.Encode(t); err != nil {
+		return []byte{}, err
+	}
+	if err = encoder.Close(); err != nil {
+		return []byte{}, err
+	}
+	return buf.Bytes(), nil
+}
 
+// newCompressor return the gzip or zlib writer to w
+func newCompressor(w io.Writer, compress string, level int) (io.WriteCloser, error) {
 	switch compress {
 	case CompressGZIP:
-		gzipBuf := bytes.NewBuffer([]byte{})
-		var gw *gzip.Writer
-		gw, err = gzip.NewWriterLevel(gzipBuf, gzip.BestSpeed)
-		defer gw.Close()
-		if _, err = gw.Write(buf.Bytes()); err != nil {
-			return []byte{}, nil
-		}
-		output = gzipBuf.Bytes()
+		return gzip.NewWriterLevel(w, level)
 	case CompressZLIB:
-	case CompressNone:
-		output = buf.Bytes()
+		return zlib.NewWriterLevel(w, level)
 	default:
-		return []byte{}, errors.New(errorCompressType)
+		return nil, ErrUnsupportedCompression
 	}
-
-	return output, nil
 }
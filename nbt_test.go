@@ -0,0 +1,199 @@
+package gonbt
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestTag return a compound with some elements
+func newTestTag() *CompoundT {
+	tag := &CompoundT{Name: "root"}
+	tag.Set("id", &StringT{Value: "minecraft:stone"})
+	tag.Set("Count", &ByteT{Value: 64})
+	tag.Set("Heights", &LongArrayT{Value: []int64{1, 2, 3}})
+	return tag
+}
+
+func TestDetectCompression(t *testing.T) {
+	t.Run("should be ok with the compression headers", func(t *testing.T) {
+		assert.EqualValues(t, CompressGZIP, detectCompression([]byte{0x1f, 0x8b, 0x08}))
+		assert.EqualValues(t, CompressZLIB, detectCompression([]byte{0x78, 0x01}))
+		assert.EqualValues(t, CompressZLIB, detectCompression([]byte{0x78, 0x5e}))
+		assert.EqualValues(t, CompressZLIB, detectCompression([]byte{0x78, 0x9c}))
+		assert.EqualValues(t, CompressZLIB, detectCompression([]byte{0x78, 0xda}))
+		assert.EqualValues(t, CompressNone, detectCompression([]byte{0x78, 0x00}))
+		assert.EqualValues(t, CompressNone, detectCompression([]byte{TagCompound, 0x00, 0x00}))
+		assert.EqualValues(t, CompressNone, detectCompression([]byte{}))
+	})
+}
+
+func TestMarshalLevel(t *testing.T) {
+	t.Run("should return an error because the compression type is unknown", func(t *testing.T) {
+		data, err := MarshalLevel(newTestTag(), "lz4", BestSpeed)
+		if assert.Error(t, err) {
+			assert.EqualValues(t, errorCompressType, err.Error())
+			assert.Empty(t, data)
+		}
+	})
+	t.Run("should return an error because the level is invalid", func(t *testing.T) {
+		data, err := MarshalLevel(newTestTag(), CompressZLIB, 42)
+		if assert.Error(t, err) {
+			assert.Empty(t, data)
+		}
+	})
+	t.Run("should be ok with a round trip on all the compressions and levels", func(t *testing.T) {
+		for _, compress := range []string{CompressGZIP, CompressZLIB, CompressNone} {
+			for _, level := range []int{NoCompression, BestSpeed, BestCompression, DefaultCompression} {
+				data, err := MarshalLevel(newTestTag(), compress, level)
+				if assert.NoError(t, err) {
+					assert.EqualValues(t, compress, detectCompression(data))
+					tag, err := Unmarshal(data)
+					if assert.NoError(t, err) {
+						assert.EqualValues(t, newTestTag(), tag)
+					}
+				}
+			}
+		}
+	})
+}
+
+func TestMarshalLE(t *testing.T) {
+	t.Run("should be ok with the little-endian byte order", func(t *testing.T) {
+		tag := &CompoundT{Name: "ab"}
+		tag.Set("i", &IntT{Value: 0x01020304})
+		expectedData := []byte{
+			TagCompound, 0x02, 0x00, 'a', 'b',
+			TagInt, 0x01, 0x00, 'i', 0x04, 0x03, 0x02, 0x01,
+			TagEnd,
+		}
+
+		data, err := MarshalLE(tag, CompressNone)
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, expectedData, data)
+		}
+	})
+	t.Run("should be ok with a round trip on all the compressions", func(t *testing.T) {
+		for _, compress := range []string{CompressGZIP, CompressZLIB, CompressNone} {
+			data, err := MarshalLE(newTestTag(), compress)
+			if assert.NoError(t, err) {
+				tag, err := UnmarshalLE(data)
+				if assert.NoError(t, err) {
+					assert.EqualValues(t, newTestTag(), tag)
+				}
+			}
+		}
+	})
+}
+
+func TestMarshalMUTF8(t *testing.T) {
+	t.Run("should be ok with the modified UTF-8 of java and the UTF-8 of bedrock", func(t *testing.T) {
+		tag := &StringT{Value: "\x00😀"}
+
+		data, err := Marshal(tag, CompressNone)
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, []byte{TagString, 0x00, 0x00, 0x00, 0x08, 0xc0, 0x80, 0xed, 0xa0, 0xbd, 0xed, 0xb8, 0x80}, data)
+			decoded, err := Unmarshal(data)
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, tag, decoded)
+			}
+		}
+		data, err = MarshalLE(tag, CompressNone)
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, []byte{TagString, 0x00, 0x00, 0x05, 0x00, 0x00, 0xf0, 0x9f, 0x98, 0x80}, data)
+		}
+	})
+	t.Run("should return an error because the string is too long", func(t *testing.T) {
+		_, err := Marshal(&StringT{Value: strings.Repeat("a", 70000)}, CompressNone)
+		if assert.Error(t, err) {
+			assert.EqualValues(t, errorStringLength, err.Error())
+		}
+	})
+}
+
+func TestUnmarshalFloats(t *testing.T) {
+	t.Run("should be ok with a bit-exact round trip of the special floats", func(t *testing.T) {
+		tag := &CompoundT{}
+		tag.Set("nan", &FloatT{Value: float32(math.NaN())})
+		tag.Set("inf", &DoubleT{Value: math.Inf(-1)})
+		tag.Set("zero", &DoubleT{Value: math.Copysign(0, -1)})
+		tag.Set("pos", &ListT{ElemType: TagDouble, Value: []interface{}{&DoubleT{Value: 0.1}, &DoubleT{Value: 64.5}, &DoubleT{Value: -7}}})
+
+		data, err := Marshal(tag, CompressGZIP)
+		if assert.NoError(t, err) {
+			decoded, err := Unmarshal(data)
+			if assert.NoError(t, err) {
+				compound := decoded.(*CompoundT)
+				assert.True(t, math.IsNaN(float64(compound.Value["nan"].(*FloatT).Value)))
+				assert.True(t, math.IsInf(compound.Value["inf"].(*DoubleT).Value, -1))
+				assert.True(t, math.Signbit(compound.Value["zero"].(*DoubleT).Value))
+				assert.EqualValues(t, tag.Value["pos"], compound.Value["pos"])
+			}
+		}
+	})
+}
+
+func TestUnmarshalWith(t *testing.T) {
+	t.Run("should return an error because the depth exceeds the default limit", func(t *testing.T) {
+		_, err := Unmarshal(nestedLists(DefaultMaxDepth + 1))
+		assert.True(t, errors.Is(err, ErrMaxDepth))
+		_, err = UnmarshalWith(nestedLists(DefaultMaxDepth+1), UnmarshalOptions{Limits: DefaultLimits()})
+		assert.True(t, errors.Is(err, ErrMaxDepth))
+	})
+	t.Run("should be ok without limit", func(t *testing.T) {
+		_, err := UnmarshalWith(nestedLists(DefaultMaxDepth+1), UnmarshalOptions{})
+		assert.NoError(t, err)
+	})
+	t.Run("should return an error because the array is too long", func(t *testing.T) {
+		data := []byte{TagIntArray, 0x00, 0x01, 'a', 0x7f, 0xff, 0xff, 0xff}
+
+		_, err := UnmarshalWith(data, UnmarshalOptions{Limits: Limits{MaxLength: 1024}})
+		assert.True(t, errors.Is(err, ErrMaxLength))
+	})
+	t.Run("should return an error because the root tag has too many bytes", func(t *testing.T) {
+		data, err := Marshal(newTestTag(), CompressNone)
+		if assert.NoError(t, err) {
+			tag, err := UnmarshalWith(append(data, data...), UnmarshalOptions{Limits: Limits{MaxBytes: int64(len(data))}})
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, newTestTag(), tag)
+			}
+			_, err = UnmarshalWith(data, UnmarshalOptions{Limits: Limits{MaxBytes: int64(len(data) - 1)}})
+			var decodeErr *DecodeError
+			if assert.True(t, errors.As(err, &decodeErr)) {
+				assert.True(t, errors.Is(err, ErrMaxBytes))
+			}
+		}
+	})
+	t.Run("should return an error because the inflated data is too large", func(t *testing.T) {
+		data, err := Marshal(&ByteArrayT{Value: make([]byte, 1<<20)}, CompressGZIP)
+		if assert.NoError(t, err) {
+			_, err = UnmarshalWith(data, UnmarshalOptions{Limits: Limits{MaxInflated: 1 << 16}})
+			assert.True(t, errors.Is(err, ErrMaxInflated))
+			_, err = UnmarshalWith(data, UnmarshalOptions{Limits: Limits{MaxBytes: 1 << 16}})
+			assert.True(t, errors.Is(err, ErrMaxBytes))
+			tag, err := UnmarshalWith(data, UnmarshalOptions{Limits: Limits{MaxBytes: 1<<20 + 16, MaxInflated: 1<<20 + 16}})
+			if assert.NoError(t, err) {
+				assert.Len(t, tag.(*ByteArrayT).Value, 1<<20)
+			}
+		}
+	})
+	t.Run("should return an error because the string is malformed", func(t *testing.T) {
+		data := []byte{TagString, 0x00, 0x00, 0x00, 0x03, 0xed, 0xa0, 0xbd}
+
+		_, err := UnmarshalWith(data, UnmarshalOptions{})
+		if assert.Error(t, err) {
+			assert.EqualValues(t, "decode TAG_String, offset 8: "+errorMUTF8, err.Error())
+		}
+	})
+	t.Run("should be ok with the raw bytes of the malformed string", func(t *testing.T) {
+		data := []byte{TagString, 0x00, 0x00, 0x00, 0x03, 0xed, 0xa0, 0xbd}
+
+		tag, err := UnmarshalWith(data, UnmarshalOptions{RawStrings: true})
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, &StringT{Value: "\xed\xa0\xbd"}, tag)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package gonbt
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+// pathNode is a node of the tree of the requested paths
+type pathNode struct {
+	// all is set when the whole element is requested
+	all      bool
+	children map[string]*pathNode
+}
+
+// newPathTree return the tree of the paths, the names of a path are separated by dots
+func newPathTree(paths []string) *pathNode {
+	root := &pathNode{}
+	for _, path := range paths {
+		node := root
+		for _, name := range strings.Split(path, ".") {
+			if node.all {
+				break
+			}
+			if node.children == nil {
+				node.children = make(map[string]*pathNode)
+			}
+			child, ok := node.children[name]
+			if !ok {
+				child = &pathNode{}
+				node.children[name] = child
+			}
+			node = child
+		}
+		// a path which contains the others replace them
+		node.all = true
+		node.children = nil
+	}
+	return root
+}
+
+// UnmarshalPaths decode only the elements of the root compound at the paths, like
+// Data.LevelName, the other elements are skipped without being decoded. It return
+// a compound with the elements found and their parent compounds, the root name
+// is not a part of the paths. The gzip and zlib compressions are detected
+func UnmarshalPaths(data []byte, paths ...string) (*CompoundT, error) {
+	var err error
+	var tagT byte
+	var name string
+	var t *CompoundT
+
+	limits := DefaultLimits()
+	if data, err = inflate(data, detectCompression(data), limits); err != nil {
+		return nil, err
+	}
+	r := newBytesReader(data, binary.BigEndian, limits)
+	if tagT, err = r.Byte(); err != nil {
+		return nil, err
+	}
+	if name, err = r.String(); err != nil {
+		err = decodeError(err, "", tagT)
+	} else if tagT != TagCompound {
+		err = decodeError(errors.New(errorMismatchType), "", tagT)
+	} else if t, err = readPaths(r, newPathTree(paths)); err != nil {
+		err = decodeError(err, "", tagT)
+	}
+	if err != nil {
+		if decodeErr, ok := err.(*DecodeError); ok {
+			decodeErr.Offset = int64(r.pos)
+		}
+		return nil, err
+	}
+	t.Name = name
+	return t, nil
+}
+
+// readPaths read the payload of a compound, the elements out of the node are skipped
+func readPaths(reader Reader, node *pathNode) (*CompoundT, error) {
+	var err error
+	var tagT byte
+	t := &CompoundT{}
+
+	if err = enter(reader); err != nil {
+		return nil, err
+	}
+	defer leave(reader)
+	for tagT, err = reader.Byte(); tagT != TagEnd && err == nil; tagT, err = reader.Byte() {
+		var name string
+		var elem Tag
+
+		if name, err = reader.String(); err != nil {
+			return nil, err
+		}
+		child, ok := node.children[name]
+		switch {
+		case ok && child.all:
+			if elem, err = NewTag(tagT, name); err != nil {
+				return nil, decodeError(err, name, tagT)
+			}
+			if err = elem.Read(reader); err != nil {
+				return nil, decodeError(err, name, tagT)
+			}
+			t.Set(name, elem)
+		case ok && tagT == TagCompound:
+			var compound *CompoundT
+			if compound, err = readPaths(reader, child); err != nil {
+				return nil, decodeError(err, name, tagT)
+			}
+			if len(compound.Value) > 0 {
+				t.Set(name, compound)
+			}
+		default:
+			if err = skipTag(reader, tagT); err != nil {
+				return nil, decodeError(err, name, tagT)
+			}
+		}
+	}
+	if err != nil {
+		return nil, truncated(err)
+	}
+	return t, nil
+}
@@ -0,0 +1,121 @@
+package gonbt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalPaths(t *testing.T) {
+	data, err := Marshal(newBenchChunk(), CompressGZIP)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	t.Run("should be ok with the requested elements only", func(t *testing.T) {
+		ret, err := UnmarshalPaths(data, "xPos", "Status", "Missing")
+		if assert.NoError(t, err) {
+			expected := &CompoundT{}
+			expected.Set("xPos", &IntT{Value: 3})
+			expected.Set("Status", &StringT{Value: "minecraft:full"})
+			assert.Equal(t, expected, ret)
+		}
+	})
+	t.Run("should be ok with the nested elements and their parents", func(t *testing.T) {
+		level := &CompoundT{Name: "root"}
+		player := &CompoundT{}
+		player.Set("Health", &FloatT{Value: 20})
+		player.Set("Score", &IntT{Value: 12})
+		world := &CompoundT{}
+		world.Set("LevelName", &StringT{Value: "world"})
+		world.Set("Player", player)
+		world.Set("Seeds", &LongArrayT{Value: []int64{1, 2, 3}})
+		level.Set("Data", world)
+		level.Set("Other", newBenchChunk())
+		data, err := Marshal(level, CompressNone)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		ret, err := UnmarshalPaths(data, "Data.LevelName", "Data.Player.Score", "Other.Missing")
+		if assert.NoError(t, err) {
+			expectedPlayer := &CompoundT{}
+			expectedPlayer.Set("Score", &IntT{Value: 12})
+			expectedWorld := &CompoundT{}
+			expectedWorld.Set("LevelName", &StringT{Value: "world"})
+			expectedWorld.Set("Player", expectedPlayer)
+			expected := &CompoundT{Name: "root"}
+			expected.Set("Data", expectedWorld)
+			assert.Equal(t, expected, ret)
+		}
+	})
+	t.Run("should be ok with a path which contains the other paths", func(t *testing.T) {
+		ret, err := UnmarshalPaths(data, "sections", "sections.Y")
+		if assert.NoError(t, err) {
+			sections, ok := ret.Get("sections")
+			if assert.True(t, ok) {
+				assert.Len(t, sections.(*ListT).Value, 24)
+			}
+		}
+		ret, err = UnmarshalPaths(data, "sections.Y", "sections")
+		if assert.NoError(t, err) {
+			_, ok := ret.Get("sections")
+			assert.True(t, ok)
+		}
+	})
+	t.Run("should be ok with an empty compound without paths", func(t *testing.T) {
+		ret, err := UnmarshalPaths(data)
+		if assert.NoError(t, err) {
+			assert.Empty(t, ret.Value)
+		}
+	})
+	t.Run("should return an error because the root is not a compound", func(t *testing.T) {
+		_, err := UnmarshalPaths([]byte{TagByte, 0x00, 0x00, 0x01}, "a")
+		var decodeErr *DecodeError
+		if assert.True(t, errors.As(err, &decodeErr)) {
+			assert.EqualValues(t, TagByte, decodeErr.Tag)
+		}
+	})
+	t.Run("should return an error with the path because a skipped element is truncated", func(t *testing.T) {
+		data, err := Marshal(newBenchChunk(), CompressNone)
+		if !assert.NoError(t, err) {
+			return
+		}
+		_, err = UnmarshalPaths(data[:len(data)-100], "xPos")
+		var decodeErr *DecodeError
+		if assert.True(t, errors.As(err, &decodeErr)) {
+			assert.True(t, errors.Is(err, ErrTruncated))
+			assert.Equal(t, "sections", decodeErr.Path)
+			// the offset of the value which is cut
+			assert.EqualValues(t, len(data)-101, decodeErr.Offset)
+		}
+	})
+	t.Run("should allocate less than Unmarshal", func(t *testing.T) {
+		data, err := Marshal(newBenchChunk(), CompressNone)
+		if !assert.NoError(t, err) {
+			return
+		}
+		partial := testing.AllocsPerRun(10, func() {
+			_, _ = UnmarshalPaths(data, "xPos")
+		})
+		full := testing.AllocsPerRun(10, func() {
+			_, _ = Unmarshal(data)
+		})
+		assert.Less(t, partial*10, full)
+	})
+}
+
+func BenchmarkUnmarshalPaths(b *testing.B) {
+	data, err := Marshal(newBenchChunk(), CompressNone)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err = UnmarshalPaths(data, "xPos", "zPos"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,389 @@
+package gonbt
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+)
+
+// operations of a Patch
+const (
+	// OpAdd set the element name of the compounds, or insert the element in the lists at
+	// the index or at the end with []. A negative index is counted from the end, -1 append
+	OpAdd = "add"
+	// OpRemove remove the matching elements
+	OpRemove = "remove"
+	// OpReplace replace the matching elements, which must exist
+	OpReplace = "replace"
+	// OpMove remove the element From, which must match one element, and add it to Path
+	OpMove = "move"
+)
+
+// Operation of a Patch. The paths have the syntax of ParsePath
+type Operation struct {
+	Op   string
+	Path string
+	// From is the source path of a move
+	From string
+	// Before is the name of the compound element before which an add insert a new
+	// element, it's added at the end of the compound when Before is empty
+	Before string
+	// Value of an add or a replace, each matching element get a copy of it
+	Value Tag
+}
+
+// operationJSON is the json of an Operation, the value is written in snbt,
+// or in binary nbt when it can't be written in snbt like the NaN values
+type operationJSON struct {
+	Op     string `json:"op"`
+	Path   string `json:"path"`
+	From   string `json:"from,omitempty"`
+	Before string `json:"before,omitempty"`
+	Value  string `json:"value,omitempty"`
+	NBT    []byte `json:"nbt,omitempty"`
+}
+
+// MarshalJSON return the json of the operation, like {"op":"add","path":"a.b","value":"1b"}.
+// The values with NaN or infinite floats are written in base64 binary nbt, like
+// {"op":"add","path":"a","nbt":"BQAAf8AAAA=="}
+func (o Operation) MarshalJSON() ([]byte, error) {
+	var err error
+
+	op := operationJSON{Op: o.Op, Path: o.Path, From: o.From, Before: o.Before}
+	if o.Value != nil && !finite(o.Value) {
+		if op.NBT, err = Marshal(o.Value, CompressNone); err != nil {
+			return nil, err
+		}
+	} else if o.Value != nil {
+		if op.Value, err = MarshalSNBT(o.Value, SNBTOptions{}); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(op)
+}
+
+// finite report if the floats and doubles of t are finite numbers, which can be written in snbt
+func finite(t Tag) bool {
+	ret := true
+	Walk(t, func(path Path, t Tag) error {
+		switch tag := t.(type) {
+		case *FloatT:
+			ret = !math.IsNaN(float64(tag.Value)) && !math.IsInf(float64(tag.Value), 0)
+		case *DoubleT:
+			ret = !math.IsNaN(tag.Value) && !math.IsInf(tag.Value, 0)
+		}
+		if !ret {
+			return SkipAll
+		}
+		return nil
+	})
+	return ret
+}
+
+// UnmarshalJSON set the operation from the json of MarshalJSON
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	var err error
+	var op operationJSON
+	var value Tag
+
+	if err = json.Unmarshal(data, &op); err != nil {
+		return err
+	}
+	if op.NBT != nil {
+		if value, err = Unmarshal(op.NBT); err != nil {
+			return err
+		}
+	} else if op.Value != "" {
+		if value, err = ParseSNBT(op.Value); err != nil {
+			return err
+		}
+	}
+	*o = Operation{Op: op.Op, Path: op.Path, From: op.From, Before: op.Before, Value: value}
+	return nil
+}
+
+// Patch is a list of operations applied in order, it can be written in json
+type Patch []Operation
+
+// Apply the operations to t and return the patch which undo them. The operations are
+// checked on a copy of t before being applied, t is not modified when one fails
+func (p Patch) Apply(t Tag) (Patch, error) {
+	var err error
+	var undo Patch
+
+	if _, err = p.apply(Clone(t)); err != nil {
+		return nil, err
+	}
+	if undo, err = p.apply(t); err != nil {
+		return nil, err
+	}
+	return undo, nil
+}
+
+// apply the operations to t and return the patch which undo them
+func (p Patch) apply(t Tag) (Patch, error) {
+	var undo Patch
+
+	for i, op := range p {
+		inverse, err := op.apply(t)
+		if err != nil {
+			return nil, &PatchError{Index: i, Op: op.Op, Path: op.Path, Err: err}
+		}
+		undo = append(undo, inverse...)
+	}
+	for i, j := 0, len(undo)-1; i < j; i, j = i+1, j-1 {
+		undo[i], undo[j] = undo[j], undo[i]
+	}
+	return undo, nil
+}
+
+// apply the operation to t and return the operations which undo it, in the order
+// of the changes
+func (o Operation) apply(t Tag) (Patch, error) {
+	var err error
+	var path, from Path
+	var values []Tag
+	var undo, inverse Patch
+
+	if path, err = ParsePath(o.Path); err != nil {
+		return nil, err
+	}
+	switch o.Op {
+	case OpAdd:
+		if o.Value == nil {
+			return nil, errors.New(errorPatchValue)
+		}
+		if !addable(path) {
+			return nil, errors.New(errorPatchAdd)
+		}
+		return addPath(t, path, o.Before, o.Value)
+	case OpRemove:
+		_, undo, err = removePath(t, path)
+		return undo, err
+	case OpReplace:
+		if o.Value == nil {
+			return nil, errors.New(errorPatchValue)
+		}
+		return replacePath(t, path, o.Value)
+	case OpMove:
+		if from, err = ParsePath(o.From); err != nil {
+			return nil, err
+		}
+		if !addable(path) {
+			return nil, errors.New(errorPatchMoveTarget)
+		}
+		if len(from.resolve(t)) > 1 {
+			return nil, errors.New(errorPatchMove)
+		}
+		if values, undo, err = removePath(t, from); err != nil {
+			return nil, err
+		}
+		if inverse, err = addPath(t, path, o.Before, values[0]); err != nil {
+			return nil, err
+		}
+		return append(undo, inverse...), nil
+	}
+	return nil, errors.New(errorPatchOp)
+}
+
+// addable report if the elements can be added at the path, which must end with
+// a name, an index or []
+func addable(path Path) bool {
+	switch path.elems[len(path.elems)-1].kind {
+	case pathName, pathIndex, pathAll:
+		return true
+	}
+	return false
+}
+
+// addPath add a copy of value to the parents of the path in t, the new compound
+// elements are inserted before the element before
+func addPath(t Tag, path Path, before string, value Tag) (Patch, error) {
+	var err error
+	var undo Patch
+
+	last := path.elems[len(path.elems)-1]
+	parents := Path{elems: path.elems[:len(path.elems)-1]}.resolve(t)
+	for _, parent := range parents {
+		if last.kind == pathName {
+			compound, ok := parent.tag.(*CompoundT)
+			if !ok {
+				continue
+			}
+			elemPath := parent.path.child(last.name).String()
+			if old, ok := compound.Get(last.name); ok && before == "" {
+				// the element keeps its position
+				compound.Set(last.name, Clone(value))
+				undo = append(undo, Operation{Op: OpReplace, Path: elemPath, Value: old})
+				continue
+			} else if ok {
+				// the element is moved before the element before, the undo remove it
+				// then add it back at its position
+				undo = append(undo, Operation{Op: OpAdd, Path: elemPath, Before: nextKey(compound, last.name), Value: old},
+					Operation{Op: OpRemove, Path: elemPath})
+			} else {
+				undo = append(undo, Operation{Op: OpRemove, Path: elemPath})
+			}
+			if err = insertKey(compound, last.name, before, Clone(value)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		elems, ok := listElems(parent.tag)
+		if !ok {
+			continue
+		}
+		i := len(elems)
+		if last.kind == pathIndex {
+			if i, ok = resolveIndex(last.index, len(elems)+1); !ok {
+				continue
+			}
+		}
+		if err = insert(parent.tag, i, Clone(value)); err != nil {
+			return nil, err
+		}
+		undo = append(undo, Operation{Op: OpRemove, Path: parent.path.index(i).String()})
+	}
+	if len(undo) == 0 {
+		return nil, ErrNoMatch
+	}
+	return undo, nil
+}
+
+// removePath remove the elements of the path in t and return them
+func removePath(t Tag, path Path) ([]Tag, Patch, error) {
+	var values []Tag
+	var undo Patch
+
+	matches := path.resolve(t)
+	for _, m := range matches {
+		if len(m.path.elems) == 0 {
+			return nil, nil, errors.New(errorPatchRoot)
+		}
+	}
+	// the last elements are removed first to keep the indexes of the others
+	for i := len(matches) - 1; i >= 0; i-- {
+		m := matches[i]
+		inverse := Operation{Op: OpAdd, Path: m.path.String(), Value: m.tag}
+		if last := m.path.elems[len(m.path.elems)-1]; last.kind == pathName {
+			// the undo insert the element at its position in the compound
+			parents, _ := Path{elems: m.path.elems[:len(m.path.elems)-1]}.Get(t)
+			if compound, ok := parents[0].(*CompoundT); ok {
+				inverse.Before = nextKey(compound, last.name)
+			}
+		}
+		if n, _ := m.path.Remove(t); n == 0 {
+			continue
+		}
+		values = append(values, m.tag)
+		undo = append(undo, inverse)
+	}
+	if len(values) == 0 {
+		return nil, nil, ErrNoMatch
+	}
+	return values, undo, nil
+}
+
+// replacePath replace the elements of the path in t by a copy of value
+func replacePath(t Tag, path Path, value Tag) (Patch, error) {
+	var err error
+	var undo Patch
+
+	matches := path.resolve(t)
+	for _, m := range matches {
+		if _, err = m.path.Set(t, Clone(value)); err != nil {
+			return nil, err
+		}
+		undo = append(undo, Operation{Op: OpReplace, Path: m.path.String(), Value: m.tag})
+	}
+	if len(undo) == 0 {
+		return nil, ErrNoMatch
+	}
+	return undo, nil
+}
+
+// nextKey return the name of the element after name in the writing order of t,
+// or an empty string for the last element
+func nextKey(t *CompoundT, name string) string {
+	keys := t.Keys()
+	for i, key := range keys {
+		if key == name && i+1 < len(keys) {
+			return keys[i+1]
+		}
+	}
+	return ""
+}
+
+// insertKey set the element name of t before the element before, or at the end of the
+// writing order when before is empty. The names not in Order are added to it, before
+// them, to keep the writing order
+func insertKey(t *CompoundT, name, before string, value Tag) error {
+	keys := t.Keys()
+	order := make([]string, 0, len(keys)+1)
+	found := before == ""
+	for _, key := range keys {
+		if key == before {
+			order = append(order, name)
+			found = true
+		}
+		if key != name {
+			order = append(order, key)
+		}
+	}
+	if !found {
+		return errors.New(errorPatchBefore)
+	}
+	if before == "" {
+		order = append(order, name)
+	}
+	t.Order = order
+	t.Set(name, value)
+	return nil
+}
+
+// insert value at the index i of the list or the array t, in a new slice because
+// the current one may be shared
+func insert(t Tag, i int, value Tag) error {
+	var err error
+	var tagT, valueT byte
+
+	switch tag := t.(type) {
+	case *ListT:
+		if tagT, err = tag.elemType(); err != nil {
+			return err
+		}
+		if valueT, err = TagType(value); err != nil {
+			return err
+		}
+		if tagT != TagEnd && tagT != valueT {
+			return ErrListType
+		}
+		setName(value, "")
+		elems := make([]interface{}, 0, len(tag.Value)+1)
+		tag.Value = append(append(append(elems, tag.Value[:i]...), value), tag.Value[i:]...)
+		tag.ElemType = valueT
+	case *ByteArrayT:
+		v, ok := value.(*ByteT)
+		if !ok {
+			return ErrListType
+		}
+		elems := make([]byte, 0, len(tag.Value)+1)
+		tag.Value = append(append(append(elems, tag.Value[:i]...), v.Value), tag.Value[i:]...)
+	case *IntArrayT:
+		v, ok := value.(*IntT)
+		if !ok {
+			return ErrListType
+		}
+		elems := make([]int32, 0, len(tag.Value)+1)
+		tag.Value = append(append(append(elems, tag.Value[:i]...), v.Value), tag.Value[i:]...)
+	case *LongArrayT:
+		v, ok := value.(*LongT)
+		if !ok {
+			return ErrListType
+		}
+		elems := make([]int64, 0, len(tag.Value)+1)
+		tag.Value = append(append(append(elems, tag.Value[:i]...), v.Value), tag.Value[i:]...)
+	}
+	return nil
+}
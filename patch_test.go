@@ -0,0 +1,223 @@
+package gonbt
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// applyAndUndo apply the patch to a copy of the tag, check the undo patch and
+// return the patched tag
+func applyAndUndo(t *testing.T, tag Tag, patch Patch) Tag {
+	patched := Clone(tag)
+	undo, err := patch.Apply(patched)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	restored := Clone(patched)
+	if _, err = undo.Apply(restored); assert.NoError(t, err) {
+		// the undo restore the order of the compounds too
+		expected, _ := Marshal(tag, CompressNone)
+		data, _ := Marshal(restored, CompressNone)
+		assert.Equal(t, expected, data, compactSNBT(restored))
+	}
+	return patched
+}
+
+func TestPatch_Apply(t *testing.T) {
+	t.Run("should be ok with the add operations and undo them", func(t *testing.T) {
+		patched := applyAndUndo(t, newPathTag(), Patch{
+			{Op: OpAdd, Path: `Health`, Value: &FloatT{Value: 20}},
+			{Op: OpAdd, Path: `"dotted.name"`, Value: &ByteT{Value: 2}},
+			{Op: OpAdd, Path: `Pos[0]`, Value: &DoubleT{Value: 0}},
+			{Op: OpAdd, Path: `Pos[-1]`, Value: &DoubleT{Value: 9}},
+			{Op: OpAdd, Path: `Inventory[].tag.Lore[]`, Value: &StringT{Value: "c"}},
+			{Op: OpAdd, Path: `Seeds[1]`, Value: &LongT{Value: 5}},
+		})
+		pos, _ := patched.(*CompoundT).GetList("Pos")
+		assert.Equal(t, `[0.0d,1.5d,64.0d,-3.0d,9.0d]`, compactSNBT(pos))
+		health, _ := patched.(*CompoundT).GetFloat("Health")
+		assert.EqualValues(t, 20, health)
+		dotted, _ := patched.(*CompoundT).GetByte("dotted.name")
+		assert.EqualValues(t, 2, dotted)
+		lore, _ := MustParsePath(`Inventory[0].tag.Lore[]`).Get(patched)
+		assert.Len(t, lore, 3)
+		seeds, _ := patched.(*CompoundT).GetLongArray("Seeds")
+		assert.Equal(t, []int64{1, 5, 2, 3}, seeds)
+	})
+	t.Run("should be ok with the remove operations and undo them", func(t *testing.T) {
+		patched := applyAndUndo(t, newPathTag(), Patch{
+			{Op: OpRemove, Path: `Inventory[{id:"minecraft:stone"}]`},
+			{Op: OpRemove, Path: `Pos[1]`},
+			{Op: OpRemove, Path: `Seeds[0]`},
+			{Op: OpRemove, Path: `"dotted.name"`},
+		})
+		assert.Equal(t, `{Pos:[1.5d,-3.0d],Seeds:[L;2L,3L],Inventory:[{Slot:1b,id:"minecraft:dirt",Count:1b}]}`, compactSNBT(patched))
+	})
+	t.Run("should be ok with the replace operations and undo them", func(t *testing.T) {
+		patched := applyAndUndo(t, newPathTag(), Patch{
+			{Op: OpReplace, Path: `Inventory[].Count`, Value: &ByteT{Value: 1}},
+			{Op: OpReplace, Path: `Inventory[0].tag.display`, Value: &StringT{Value: "none"}},
+			{Op: OpReplace, Path: `Seeds[-1]`, Value: &LongT{Value: 9}},
+		})
+		counts, _ := MustParsePath(`Inventory[{Count:1b}]`).Get(patched)
+		assert.Len(t, counts, 3)
+		display, _ := MustParsePath(`Inventory[0].tag.display`).Get(patched)
+		assert.Equal(t, []Tag{&StringT{Name: "display", Value: "none"}}, display)
+		seeds, _ := patched.(*CompoundT).GetLongArray("Seeds")
+		assert.Equal(t, []int64{1, 2, 9}, seeds)
+	})
+	t.Run("should be ok with the move operations and undo them", func(t *testing.T) {
+		patched := applyAndUndo(t, newPathTag(), Patch{
+			{Op: OpMove, From: `Inventory[{Slot:1b}]`, Path: `Inventory[0]`},
+			{Op: OpMove, From: `Inventory[1].tag`, Path: `Tag`},
+			{Op: OpMove, From: `Pos[0]`, Path: `Pos[]`},
+		})
+		ids, _ := MustParsePath(`Inventory[].id`).Get(patched)
+		assert.Equal(t, []Tag{&StringT{Name: "id", Value: "minecraft:dirt"}, &StringT{Name: "id", Value: "minecraft:stone"},
+			&StringT{Name: "id", Value: "minecraft:stone"}}, ids)
+		names, _ := MustParsePath(`Tag.display.Name`).Get(patched)
+		assert.Equal(t, []Tag{&StringT{Name: "Name", Value: "Rock"}}, names)
+		_, err := MustParsePath(`Inventory[].tag`).Get(patched)
+		assert.Equal(t, ErrNoMatch, err)
+		pos, _ := patched.(*CompoundT).GetList("Pos")
+		assert.Equal(t, `[64.0d,-3.0d,1.5d]`, compactSNBT(pos))
+	})
+	t.Run("should be ok and undo the operations in the order of the compounds", func(t *testing.T) {
+		tag, _ := ParseSNBT(`{a:1,b:2,c:3,f:4,d:5,ba:6,g:{x:1,y:2}}`)
+
+		patched := applyAndUndo(t, tag, Patch{{Op: OpRemove, Path: `b`}})
+		assert.Equal(t, []string{"a", "c", "f", "d", "ba", "g"}, patched.(*CompoundT).Keys())
+		patched = applyAndUndo(t, tag, Patch{
+			{Op: OpMove, From: `g.x`, Path: `x`},
+			{Op: OpAdd, Path: `e`, Before: `c`, Value: &IntT{Value: 7}},
+			{Op: OpAdd, Path: `ba`, Before: `a`, Value: &IntT{Value: 8}},
+			{Op: OpAdd, Path: `d`, Value: &IntT{Value: 9}},
+			{Op: OpRemove, Path: `g`},
+		})
+		assert.Equal(t, `{ba:8,a:1,b:2,e:7,c:3,f:4,d:9,x:1}`, compactSNBT(patched))
+	})
+	t.Run("should be ok and undo the operations on the compounds without order", func(t *testing.T) {
+		tag := &CompoundT{Value: map[string]interface{}{
+			"c": &IntT{Name: "c", Value: 3},
+			"a": &IntT{Name: "a", Value: 1},
+			"b": &IntT{Name: "b", Value: 2},
+		}}
+
+		patched := applyAndUndo(t, tag, Patch{
+			{Op: OpRemove, Path: `c`},
+			{Op: OpRemove, Path: `a`},
+			{Op: OpAdd, Path: `d`, Value: &IntT{Value: 4}},
+		})
+		assert.Equal(t, []string{"b", "d"}, patched.(*CompoundT).Keys())
+	})
+	t.Run("should be ok and keep the slices returned before", func(t *testing.T) {
+		tag := newPathTag()
+		seeds, _ := tag.(*CompoundT).GetLongArray("Seeds")
+
+		_, err := Patch{
+			{Op: OpAdd, Path: `Seeds[0]`, Value: &LongT{Value: 9}},
+			{Op: OpRemove, Path: `Seeds[1]`},
+		}.Apply(tag)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []int64{1, 2, 3}, seeds)
+		}
+	})
+	t.Run("should return an error and keep the tag unchanged", func(t *testing.T) {
+		patches := map[string]Patch{
+			"0 (remove Missing): " + errorNoMatch: {{Op: OpRemove, Path: `Missing`}},
+			"1 (add Pos[]): " + errorListType: {
+				{Op: OpAdd, Path: `Health`, Value: &FloatT{}},
+				{Op: OpAdd, Path: `Pos[]`, Value: &StringT{}},
+			},
+			"1 (replace Pos[0]): " + errorListType: {
+				{Op: OpReplace, Path: `Pos[1]`, Value: &DoubleT{}},
+				{Op: OpReplace, Path: `Pos[0]`, Value: &IntT{}},
+			},
+			"0 (replace Pos[3]): " + errorNoMatch:                 {{Op: OpReplace, Path: `Pos[3]`, Value: &DoubleT{}}},
+			"0 (replace Pos): " + errorPatchValue:                 {{Op: OpReplace, Path: `Pos`}},
+			"0 (add Pos): " + errorPatchValue:                     {{Op: OpAdd, Path: `Pos`}},
+			"0 (add Pos[{a:1}]): " + errorPatchAdd:                {{Op: OpAdd, Path: `Pos[{a:1}]`, Value: &IntT{}}},
+			"0 (add Pos[5]): " + errorNoMatch:                     {{Op: OpAdd, Path: `Pos[5]`, Value: &DoubleT{}}},
+			"0 (add Missing.a): " + errorNoMatch:                  {{Op: OpAdd, Path: `Missing.a`, Value: &DoubleT{}}},
+			"0 (move Tag): " + errorPatchMove:                     {{Op: OpMove, From: `Inventory[]`, Path: `Tag`}},
+			"0 (move Pos[{a:1}]): " + errorPatchMoveTarget:        {{Op: OpMove, From: `Pos[0]`, Path: `Pos[{a:1}]`}},
+			"0 (add Tag): " + errorPatchBefore:                    {{Op: OpAdd, Path: `Tag`, Before: `Missing`, Value: &IntT{}}},
+			"0 (move Tag): " + errorNoMatch:                       {{Op: OpMove, From: `Missing`, Path: `Tag`}},
+			"0 (move Tag): line 1, column 3: " + errorPathName:    {{Op: OpMove, From: `a.`, Path: `Tag`}},
+			`0 (remove {"dotted.name":1b}): ` + errorPatchRoot:    {{Op: OpRemove, Path: `{"dotted.name":1b}`}},
+			"0 (move Tag): " + errorPatchRoot:                     {{Op: OpMove, From: `{"dotted.name":1b}`, Path: `Tag`}},
+			"0 (copy Tag): " + errorPatchOp:                       {{Op: "copy", Path: `Tag`}},
+			"0 (remove a..b): line 1, column 3: " + errorPathName: {{Op: OpRemove, Path: `a..b`}},
+		}
+		for msg, patch := range patches {
+			tag := newPathTag()
+
+			undo, err := patch.Apply(tag)
+			var patchErr *PatchError
+			if assert.True(t, errors.As(err, &patchErr), msg) {
+				assert.Equal(t, "patch operation "+msg, err.Error())
+				assert.Nil(t, undo)
+			}
+			assert.True(t, Equal(newPathTag(), tag), msg)
+		}
+	})
+}
+
+func TestPatch_JSON(t *testing.T) {
+	t.Run("should be ok with a round trip", func(t *testing.T) {
+		patch := Patch{
+			{Op: OpAdd, Path: `Inventory[]`, Value: &CompoundT{Value: map[string]interface{}{
+				"id": &StringT{Name: "id", Value: "minecraft:stone"},
+			}}},
+			{Op: OpRemove, Path: `Pos[0]`},
+			{Op: OpMove, From: `a`, Path: `b`, Before: `c`},
+		}
+
+		data, err := json.Marshal(patch)
+		if assert.NoError(t, err) {
+			assert.Equal(t, `[{"op":"add","path":"Inventory[]","value":"{id:\"minecraft:stone\"}"},`+
+				`{"op":"remove","path":"Pos[0]"},{"op":"move","path":"b","from":"a","before":"c"}]`, string(data))
+			var ret Patch
+			if assert.NoError(t, json.Unmarshal(data, &ret)) {
+				assert.Len(t, ret, 3)
+				assert.Equal(t, patch[1], ret[1])
+				assert.Equal(t, patch[2], ret[2])
+				assert.True(t, Equal(patch[0].Value, ret[0].Value))
+			}
+		}
+	})
+	t.Run("should be ok with a round trip of the NaN and infinite values", func(t *testing.T) {
+		value, _ := ParseSNBT(`{a:[1.0d,2.0d],b:1.5f}`)
+		value.(*CompoundT).SetFloat("b", float32(math.Inf(1)))
+		patch := Patch{
+			{Op: OpReplace, Path: `a`, Value: &DoubleT{Value: math.NaN()}},
+			{Op: OpAdd, Path: `b`, Value: value},
+		}
+
+		data, err := json.Marshal(patch)
+		if assert.NoError(t, err) {
+			assert.Contains(t, string(data), `{"op":"replace","path":"a","nbt":"BgAAf/gAAAAAAAE="}`)
+			var ret Patch
+			if assert.NoError(t, json.Unmarshal(data, &ret)) {
+				assert.Len(t, ret, 2)
+				assert.True(t, Equal(patch[0].Value, ret[0].Value))
+				assert.True(t, Equal(patch[1].Value, ret[1].Value))
+			}
+		}
+	})
+	t.Run("should return an error because the value is invalid", func(t *testing.T) {
+		var patch Patch
+
+		err := json.Unmarshal([]byte(`[{"op":"add","path":"a","value":"{"}]`), &patch)
+		var syntaxErr *SyntaxError
+		assert.True(t, errors.As(err, &syntaxErr))
+		err = json.Unmarshal([]byte(`[{"op":1}]`), &patch)
+		assert.Error(t, err)
+		_, err = json.Marshal(Patch{{Op: OpAdd, Path: "a", Value: &fakeTag{}}})
+		assert.Error(t, err)
+	})
+}
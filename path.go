@@ -0,0 +1,693 @@
+package gonbt
+
+import (
+	"strconv"
+	"strings"
+)
+
+// paths to the elements of a tag with the syntax of the minecraft commands, like:
+// Inventory[{Slot:0b}].tag.display.Name, Pos[1] or Items[]
+// https://minecraft.fandom.com/wiki/NBT_path_format
+
+// kinds of the path elements
+const (
+	// pathName is the element name of a compound: name or "quoted name"
+	pathName = iota
+	// pathNameFilter is the element name of a compound if it matches the filter: name{filter}
+	pathNameFilter
+	// pathRootFilter is the root if it matches the filter: {filter}
+	pathRootFilter
+	// pathIndex is the element of a list or an array, from the end when it's negative: [index]
+	pathIndex
+	// pathAll is all the elements of a list or an array: []
+	pathAll
+	// pathListFilter is the compounds of a list which match the filter: [{filter}]
+	pathListFilter
+)
+
+// pathElem is an element of a Path
+type pathElem struct {
+	kind   int
+	name   string
+	index  int
+	filter *CompoundT
+}
+
+// Path to the elements of a tag, one path can match several elements. A compound
+// filter matches the compounds which contain all its elements, the filter lists
+// match the lists which contain an element matching each of their elements
+type Path struct {
+	elems []pathElem
+}
+
+// ParsePath compile the path s, like Inventory[{Slot:0b}].tag.display.Name
+func ParsePath(s string) (Path, error) {
+	var err error
+	var path Path
+
+	p := &snbtParser{data: s}
+	if s == "" {
+		return Path{}, p.error(errorPathName)
+	}
+	for p.pos < len(p.data) {
+		var elem pathElem
+
+		if elem, err = p.pathElem(len(path.elems) == 0); err != nil {
+			return Path{}, err
+		}
+		path.elems = append(path.elems, elem)
+		switch p.peek() {
+		case 0, '[', '{':
+		case '.':
+			p.pos++
+			if p.pos == len(p.data) {
+				return Path{}, p.error(errorPathName)
+			}
+		default:
+			return Path{}, p.error(errorPathDot)
+		}
+	}
+	return path, nil
+}
+
+// MustParsePath is like ParsePath but panics if s can't be parsed
+func MustParsePath(s string) Path {
+	path, err := ParsePath(s)
+	if err != nil {
+		panic(`gonbt: ParsePath(` + strconv.Quote(s) + `): ` + err.Error())
+	}
+	return path
+}
+
+// isPathName report if c is allowed in an unquoted name of a path
+func isPathName(c byte) bool {
+	return strings.IndexByte(" \"'[].{}", c) < 0
+}
+
+// pathElem parse the next element of a path, first is set for the first element
+func (p *snbtParser) pathElem(first bool) (pathElem, error) {
+	var err error
+	var name string
+
+	switch p.peek() {
+	case '{':
+		if !first {
+			return pathElem{}, p.error(errorPathFilter)
+		}
+		elem := pathElem{kind: pathRootFilter}
+		if elem.filter, err = p.pathFilter(); err != nil {
+			return pathElem{}, err
+		}
+		return elem, nil
+	case '[':
+		return p.pathIndex()
+	case '"', '\'':
+		if name, err = p.quoted(); err != nil {
+			return pathElem{}, err
+		}
+	default:
+		start := p.pos
+		for p.pos < len(p.data) && isPathName(p.data[p.pos]) {
+			p.pos++
+		}
+		if name = p.data[start:p.pos]; name == "" {
+			return pathElem{}, p.error(errorPathName)
+		}
+	}
+
+	elem := pathElem{kind: pathName, name: name}
+	if p.peek() == '{' {
+		elem.kind = pathNameFilter
+		if elem.filter, err = p.pathFilter(); err != nil {
+			return pathElem{}, err
+		}
+	}
+	return elem, nil
+}
+
+// pathIndex parse [], [index] or [{filter}]
+func (p *snbtParser) pathIndex() (pathElem, error) {
+	var err error
+	var elem pathElem
+
+	p.pos++
+	switch p.peek() {
+	case ']':
+		p.pos++
+		return pathElem{kind: pathAll}, nil
+	case '{':
+		elem.kind = pathListFilter
+		if elem.filter, err = p.pathFilter(); err != nil {
+			return pathElem{}, err
+		}
+	default:
+		start := p.pos
+		if p.peek() == '-' {
+			p.pos++
+		}
+		for p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '9' {
+			p.pos++
+		}
+		elem.kind = pathIndex
+		if elem.index, err = strconv.Atoi(p.data[start:p.pos]); err != nil {
+			return pathElem{}, p.errorAt(start, errorPathIndex)
+		}
+	}
+	if err = p.expect(']'); err != nil {
+		return pathElem{}, err
+	}
+	return elem, nil
+}
+
+// pathFilter parse the compound of a filter
+func (p *snbtParser) pathFilter() (*CompoundT, error) {
+	var err error
+	var filter Tag
+
+	if filter, err = p.compound("", 1); err != nil {
+		return nil, err
+	}
+	return filter.(*CompoundT), nil
+}
+
+// String return the path with the syntax of ParsePath
+func (p Path) String() string {
+	var b strings.Builder
+
+	for i, elem := range p.elems {
+		switch elem.kind {
+		case pathName, pathNameFilter:
+			if i > 0 {
+				b.WriteByte('.')
+			}
+			if elem.name != "" && strings.IndexFunc(elem.name, func(r rune) bool {
+				return r < 0x80 && !isPathName(byte(r))
+			}) < 0 {
+				b.WriteString(elem.name)
+			} else {
+				b.WriteString(quoteString(elem.name))
+			}
+			if elem.kind == pathNameFilter {
+				b.WriteString(compactSNBT(elem.filter))
+			}
+		case pathRootFilter:
+			b.WriteString(compactSNBT(elem.filter))
+		case pathIndex:
+			b.WriteString("[" + strconv.Itoa(elem.index) + "]")
+		case pathAll:
+			b.WriteString("[]")
+		case pathListFilter:
+			b.WriteString("[" + compactSNBT(elem.filter) + "]")
+		}
+	}
+	return b.String()
+}
+
+// child return the path of the element name of the compound at p
+func (p Path) child(name string) Path {
+	return Path{elems: append(p.elems[:len(p.elems):len(p.elems)], pathElem{kind: pathName, name: name})}
+}
+
+// index return the path of the element i of the list at p
+func (p Path) index(i int) Path {
+	return Path{elems: append(p.elems[:len(p.elems):len(p.elems)], pathElem{kind: pathIndex, index: i})}
+}
+
+// compactSNBT return the compact snbt of t, or an empty string if it can't be written
+func compactSNBT(t Tag) string {
+	str, _ := MarshalSNBT(t, SNBTOptions{})
+	return str
+}
+
+// Get return the elements of t which match the path, or ErrNoMatch.
+// The elements of the arrays are returned as new ByteT, IntT or LongT
+func (p Path) Get(t Tag) ([]Tag, error) {
+	tags := []Tag{t}
+	for _, elem := range p.elems {
+		var next []Tag
+		for _, tag := range tags {
+			next = elem.get(tag, next)
+		}
+		tags = next
+	}
+	if len(tags) == 0 {
+		return nil, ErrNoMatch
+	}
+	return tags, nil
+}
+
+// Set value to the elements of t which match the path and return their number, the
+// missing compounds and lists of the path are created. Each element after the first
+// one get a copy of value. It return ErrNoMatch if nothing matches, and ErrListType
+// if value can't be an element of a matching list
+func (p Path) Set(t Tag, value Tag) (int, error) {
+	var err error
+	var n int
+
+	if len(p.elems) == 0 {
+		return 0, ErrNoMatch
+	}
+	tags := []Tag{t}
+	for i, elem := range p.elems[:len(p.elems)-1] {
+		var next []Tag
+		for _, tag := range tags {
+			next = elem.getOrCreate(tag, p.elems[i+1], next)
+		}
+		tags = next
+	}
+
+	used := false
+	values := func() Tag {
+		if !used {
+			used = true
+			return value
+		}
+		return Clone(value)
+	}
+	last := p.elems[len(p.elems)-1]
+	for _, tag := range tags {
+		var count int
+		count, err = last.set(tag, values)
+		if n += count; err != nil {
+			return n, err
+		}
+	}
+	if n == 0 {
+		return 0, ErrNoMatch
+	}
+	return n, nil
+}
+
+// Remove the elements of t which match the path and return their number,
+// or ErrNoMatch if nothing matches
+func (p Path) Remove(t Tag) (int, error) {
+	var n int
+
+	if len(p.elems) == 0 {
+		return 0, ErrNoMatch
+	}
+	tags := []Tag{t}
+	for _, elem := range p.elems[:len(p.elems)-1] {
+		var next []Tag
+		for _, tag := range tags {
+			next = elem.get(tag, next)
+		}
+		tags = next
+	}
+	last := p.elems[len(p.elems)-1]
+	for _, tag := range tags {
+		n += last.remove(tag)
+	}
+	if n == 0 {
+		return 0, ErrNoMatch
+	}
+	return n, nil
+}
+
+// match is an element matched by a path, with its path of names and indexes
+type match struct {
+	path Path
+	tag  Tag
+}
+
+// resolve return the elements of t which match the path like Get, with their paths
+func (p Path) resolve(t Tag) []match {
+	matches := []match{{tag: t}}
+	for _, elem := range p.elems {
+		var next []match
+		for _, m := range matches {
+			next = elem.resolve(m, next)
+		}
+		matches = next
+	}
+	return matches
+}
+
+// resolve append to out the elements of the match m which match e
+func (e pathElem) resolve(m match, out []match) []match {
+	switch e.kind {
+	case pathName, pathNameFilter:
+		for _, tag := range e.get(m.tag, nil) {
+			out = append(out, match{path: m.path.child(e.name), tag: tag})
+		}
+	case pathRootFilter:
+		if matchTag(e.filter, m.tag) {
+			out = append(out, m)
+		}
+	case pathIndex:
+		elems, _ := listElems(m.tag)
+		if i, ok := resolveIndex(e.index, len(elems)); ok {
+			if tag, ok := elems[i].(Tag); ok {
+				out = append(out, match{path: m.path.index(i), tag: tag})
+			}
+		}
+	case pathAll, pathListFilter:
+		elems, _ := listElems(m.tag)
+		for i, v := range elems {
+			if tag, ok := v.(Tag); ok && (e.kind == pathAll || matchTag(e.filter, tag)) {
+				out = append(out, match{path: m.path.index(i), tag: tag})
+			}
+		}
+	}
+	return out
+}
+
+// resolveIndex return the position of the index in a list of n elements
+func resolveIndex(index, n int) (int, bool) {
+	if index < 0 {
+		index += n
+	}
+	return index, index >= 0 && index < n
+}
+
+// get append to out the elements of t which match e
+func (e pathElem) get(t Tag, out []Tag) []Tag {
+	switch e.kind {
+	case pathName, pathNameFilter:
+		compound, ok := t.(*CompoundT)
+		if !ok {
+			return out
+		}
+		if elem, ok := compound.Get(e.name); ok && (e.kind == pathName || matchTag(e.filter, elem)) {
+			out = append(out, elem)
+		}
+	case pathRootFilter:
+		if matchTag(e.filter, t) {
+			out = append(out, t)
+		}
+	case pathIndex:
+		elems, _ := listElems(t)
+		if i, ok := resolveIndex(e.index, len(elems)); ok {
+			if elem, ok := elems[i].(Tag); ok {
+				out = append(out, elem)
+			}
+		}
+	case pathAll, pathListFilter:
+		elems, _ := listElems(t)
+		for _, v := range elems {
+			if elem, ok := v.(Tag); ok && (e.kind == pathAll || matchTag(e.filter, elem)) {
+				out = append(out, elem)
+			}
+		}
+	}
+	return out
+}
+
+// newParent return an empty tag which can contain the element next
+func newParent(next pathElem) Tag {
+	switch next.kind {
+	case pathIndex, pathAll, pathListFilter:
+		return &ListT{}
+	}
+	return &CompoundT{}
+}
+
+// getOrCreate append to out the elements of t which match e, like get. The missing
+// element of a compound is created to contain next, a list without element matching
+// a filter get a copy of the filter
+func (e pathElem) getOrCreate(t Tag, next pathElem, out []Tag) []Tag {
+	switch e.kind {
+	case pathName, pathNameFilter:
+		compound, ok := t.(*CompoundT)
+		if !ok {
+			return out
+		}
+		if _, ok = compound.Value[e.name]; ok {
+			return e.get(t, out)
+		}
+		var elem Tag = newParent(next)
+		if e.kind == pathNameFilter {
+			elem = Clone(e.filter)
+		}
+		compound.Set(e.name, elem)
+		return append(out, elem)
+	case pathAll, pathListFilter:
+		n := len(out)
+		if out = e.get(t, out); len(out) > n {
+			return out
+		}
+		list, ok := t.(*ListT)
+		if !ok {
+			return out
+		}
+		var elem Tag = newParent(next)
+		if e.kind == pathListFilter {
+			elem = Clone(e.filter)
+		} else if len(list.Value) > 0 {
+			return out
+		}
+		if list.Append(elem) != nil {
+			return out
+		}
+		return append(out, elem)
+	}
+	return e.get(t, out)
+}
+
+// set the elements of t which match e with the tags of values and return their number
+func (e pathElem) set(t Tag, values func() Tag) (int, error) {
+	switch e.kind {
+	case pathName, pathNameFilter:
+		compound, ok := t.(*CompoundT)
+		if !ok {
+			return 0, nil
+		}
+		if e.kind == pathNameFilter {
+			if elem, ok := compound.Get(e.name); !ok || !matchTag(e.filter, elem) {
+				return 0, nil
+			}
+		}
+		compound.Set(e.name, values())
+		return 1, nil
+	case pathIndex:
+		if list, ok := t.(*ListT); ok {
+			i, ok := resolveIndex(e.index, len(list.Value))
+			if !ok {
+				return 0, nil
+			}
+			return list.replace([]int{i}, values)
+		}
+		return setArray(t, e.index, values)
+	case pathAll, pathListFilter:
+		if list, ok := t.(*ListT); ok {
+			var indexes []int
+			for i, v := range list.Value {
+				if elem, ok := v.(Tag); ok && (e.kind == pathAll || matchTag(e.filter, elem)) {
+					indexes = append(indexes, i)
+				}
+			}
+			return list.replace(indexes, values)
+		}
+		if e.kind == pathAll {
+			var n int
+			elems, _ := listElems(t)
+			for i := range elems {
+				count, err := setArray(t, i, values)
+				if n += count; err != nil {
+					return n, err
+				}
+			}
+			return n, nil
+		}
+	}
+	return 0, nil
+}
+
+// replace the elements indexes of the list with the tags of values
+func (t *ListT) replace(indexes []int, values func() Tag) (int, error) {
+	var err error
+	var tagT, valueT byte
+
+	if len(indexes) == 0 {
+		return 0, nil
+	}
+	if tagT, err = t.elemType(); err != nil {
+		return 0, err
+	}
+	value := values()
+	if valueT, err = TagType(value); err != nil {
+		return 0, err
+	}
+	// the only element of a list can be replaced by an element of another type
+	if tagT != valueT && len(t.Value) > 1 {
+		return 0, ErrListType
+	}
+	for n, i := range indexes {
+		if n > 0 {
+			value = values()
+		}
+		setName(value, "")
+		t.Value[i] = value
+	}
+	t.ElemType = valueT
+	return len(indexes), nil
+}
+
+// setArray set the element index of the array t with the tag of values, which
+// must be a ByteT, an IntT or a LongT like the elements of the array
+func setArray(t Tag, index int, values func() Tag) (int, error) {
+	switch array := t.(type) {
+	case *ByteArrayT:
+		i, ok := resolveIndex(index, len(array.Value))
+		if !ok {
+			return 0, nil
+		}
+		value, ok := values().(*ByteT)
+		if !ok {
+			return 0, ErrListType
+		}
+		array.Value[i] = value.Value
+	case *IntArrayT:
+		i, ok := resolveIndex(index, len(array.Value))
+		if !ok {
+			return 0, nil
+		}
+		value, ok := values().(*IntT)
+		if !ok {
+			return 0, ErrListType
+		}
+		array.Value[i] = value.Value
+	case *LongArrayT:
+		i, ok := resolveIndex(index, len(array.Value))
+		if !ok {
+			return 0, nil
+		}
+		value, ok := values().(*LongT)
+		if !ok {
+			return 0, ErrListType
+		}
+		array.Value[i] = value.Value
+	default:
+		return 0, nil
+	}
+	return 1, nil
+}
+
+// remove the elements of t which match e and return their number
+func (e pathElem) remove(t Tag) int {
+	switch e.kind {
+	case pathName, pathNameFilter:
+		compound, ok := t.(*CompoundT)
+		if !ok {
+			return 0
+		}
+		elem, ok := compound.Get(e.name)
+		if !ok || e.kind == pathNameFilter && !matchTag(e.filter, elem) {
+			return 0
+		}
+		compound.Delete(e.name)
+		return 1
+	case pathIndex:
+		elems, _ := listElems(t)
+		i, ok := resolveIndex(e.index, len(elems))
+		if !ok {
+			return 0
+		}
+		return removeElems(t, func(j int) bool { return j == i })
+	case pathAll:
+		return removeElems(t, func(int) bool { return true })
+	case pathListFilter:
+		list, ok := t.(*ListT)
+		if !ok {
+			return 0
+		}
+		return removeElems(list, func(i int) bool {
+			elem, ok := list.Value[i].(Tag)
+			return ok && matchTag(e.filter, elem)
+		})
+	}
+	return 0
+}
+
+// removeElems remove the elements i of the list or the array t for which
+// match return true, and return their number
+func removeElems(t Tag, match func(i int) bool) int {
+	var n int
+
+	// the kept elements are copied in a new slice, the current one may be shared
+	switch tag := t.(type) {
+	case *ListT:
+		value := make([]interface{}, 0, len(tag.Value))
+		for i, elem := range tag.Value {
+			if !match(i) {
+				value = append(value, elem)
+			}
+		}
+		n, tag.Value = len(tag.Value)-len(value), value
+	case *ByteArrayT:
+		value := make([]byte, 0, len(tag.Value))
+		for i, elem := range tag.Value {
+			if !match(i) {
+				value = append(value, elem)
+			}
+		}
+		n, tag.Value = len(tag.Value)-len(value), value
+	case *IntArrayT:
+		value := make([]int32, 0, len(tag.Value))
+		for i, elem := range tag.Value {
+			if !match(i) {
+				value = append(value, elem)
+			}
+		}
+		n, tag.Value = len(tag.Value)-len(value), value
+	case *LongArrayT:
+		value := make([]int64, 0, len(tag.Value))
+		for i, elem := range tag.Value {
+			if !match(i) {
+				value = append(value, elem)
+			}
+		}
+		n, tag.Value = len(tag.Value)-len(value), value
+	}
+	return n
+}
+
+// matchTag report if t matches the filter: the compounds of t contain the elements
+// of the filter compounds, the lists of t contain an element matching each element
+// of the filter lists and the other tags are equal
+func matchTag(filter Tag, t Tag) bool {
+	switch f := filter.(type) {
+	case *CompoundT:
+		compound, ok := t.(*CompoundT)
+		if !ok {
+			return false
+		}
+		for name, v := range f.Value {
+			want, ok := v.(Tag)
+			if !ok {
+				return false
+			}
+			elem, ok := compound.Get(name)
+			if !ok || !matchTag(want, elem) {
+				return false
+			}
+		}
+		return true
+	case *ListT:
+		list, ok := t.(*ListT)
+		if !ok {
+			return false
+		}
+		if len(f.Value) == 0 {
+			return len(list.Value) == 0
+		}
+		for _, v := range f.Value {
+			want, ok := v.(Tag)
+			if !ok {
+				return false
+			}
+			found := false
+			for _, e := range list.Value {
+				if elem, ok := e.(Tag); ok && matchTag(want, elem) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+	return equal(filter, t, EqualOptions{}, false)
+}
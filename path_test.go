@@ -0,0 +1,315 @@
+package gonbt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newPathTag return a player with an inventory
+func newPathTag() Tag {
+	tag, err := ParseSNBT(`{
+		Pos: [1.5d, 64.0d, -3.0d],
+		"dotted.name": 1b,
+		Seeds: [L; 1L, 2L, 3L],
+		Inventory: [
+			{Slot: 0b, id: "minecraft:stone", Count: 64b, tag: {display: {Name: "Rock"}, Lore: ["a", "b"]}},
+			{Slot: 1b, id: "minecraft:dirt", Count: 1b},
+			{Slot: 2b, id: "minecraft:stone", Count: 3b}
+		]
+	}`)
+	if err != nil {
+		panic(err)
+	}
+	return tag
+}
+
+func TestParsePath(t *testing.T) {
+	t.Run("should be ok with all the elements", func(t *testing.T) {
+		paths := []string{
+			`Inventory[{Slot:0b}].tag.display.Name`,
+			`Pos[1]`,
+			`Pos[-1]`,
+			`Items[]`,
+			`{Slot:0b}.tag`,
+			`tag{display:{Name:Rock}}.Lore[0]`,
+			`"dotted.name"`,
+			`Inventory[][0]`,
+		}
+		for _, s := range paths {
+			path, err := ParsePath(s)
+			if assert.NoError(t, err, s) {
+				assert.Equal(t, s, path.String())
+			}
+		}
+	})
+	t.Run("should be ok with the quoted names", func(t *testing.T) {
+		path, err := ParsePath(`'with space'."with \"quote\"".a`)
+		if assert.NoError(t, err) {
+			assert.Equal(t, `"with space".'with "quote"'.a`, path.String())
+		}
+	})
+	t.Run("should return an error because the path is invalid", func(t *testing.T) {
+		paths := map[string]string{
+			``:                        "line 1, column 1: " + errorPathName,
+			`a.`:                      "line 1, column 3: " + errorPathName,
+			`a..b`:                    "line 1, column 3: " + errorPathName,
+			`a[x]`:                    "line 1, column 3: " + errorPathIndex,
+			`a[0`:                     "line 1, column 4: expected ']'",
+			`a[0]{b:1}`:               "line 1, column 5: " + errorPathFilter,
+			`a b`:                     "line 1, column 2: " + errorPathDot,
+			`a{b:}`:                   "line 1, column 5: " + errorSNBTValue,
+			`"unquoted`:               "line 1, column 10: " + errorSNBTString,
+			`a[{b:1}`:                 "line 1, column 8: expected ']'",
+			`a.{b:1}`:                 "line 1, column 3: " + errorPathFilter,
+			`a[99999999999999999999]`: "line 1, column 3: " + errorPathIndex,
+		}
+		for s, msg := range paths {
+			_, err := ParsePath(s)
+			var syntaxErr *SyntaxError
+			if assert.True(t, errors.As(err, &syntaxErr), s) {
+				assert.Equal(t, msg, err.Error(), s)
+			}
+		}
+	})
+	t.Run("should panic because the path is invalid", func(t *testing.T) {
+		assert.Panics(t, func() { MustParsePath(`a..b`) })
+		assert.NotPanics(t, func() { MustParsePath(`a.b`) })
+	})
+}
+
+func TestPath_Get(t *testing.T) {
+	tag := newPathTag()
+
+	t.Run("should be ok with one element", func(t *testing.T) {
+		tags, err := MustParsePath(`Inventory[{Slot:0b}].tag.display.Name`).Get(tag)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []Tag{&StringT{Name: "Name", Value: "Rock"}}, tags)
+		}
+		tags, err = MustParsePath(`Pos[1]`).Get(tag)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []Tag{&DoubleT{Value: 64}}, tags)
+		}
+		tags, err = MustParsePath(`Pos[-1]`).Get(tag)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []Tag{&DoubleT{Value: -3}}, tags)
+		}
+		tags, err = MustParsePath(`"dotted.name"`).Get(tag)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []Tag{&ByteT{Name: "dotted.name", Value: 1}}, tags)
+		}
+	})
+	t.Run("should be ok with several elements", func(t *testing.T) {
+		tags, err := MustParsePath(`Inventory[{id:"minecraft:stone"}].Count`).Get(tag)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []Tag{&ByteT{Name: "Count", Value: 64}, &ByteT{Name: "Count", Value: 3}}, tags)
+		}
+		tags, err = MustParsePath(`Inventory[].Slot`).Get(tag)
+		if assert.NoError(t, err) {
+			assert.Len(t, tags, 3)
+		}
+		tags, err = MustParsePath(`Seeds[]`).Get(tag)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []Tag{&LongT{Value: 1}, &LongT{Value: 2}, &LongT{Value: 3}}, tags)
+		}
+	})
+	t.Run("should be ok with the compound filters and the list filters", func(t *testing.T) {
+		tags, err := MustParsePath(`{Pos:[64.0d]}.Inventory[{tag:{Lore:["b"]}}].Slot`).Get(tag)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []Tag{&ByteT{Name: "Slot", Value: 0}}, tags)
+		}
+		tags, err = MustParsePath(`Inventory[0].tag{display:{}}.Lore[1]`).Get(tag)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []Tag{&StringT{Value: "b"}}, tags)
+		}
+	})
+	t.Run("should return an error because nothing matches", func(t *testing.T) {
+		paths := []string{
+			`Inventory[3]`,
+			`Inventory[-4]`,
+			`Inventory[{Slot:5b}]`,
+			`Inventory[{Slot:0}]`,
+			`Pos.x`,
+			`Missing`,
+			`{Pos:[]}`,
+			`Inventory[0].tag{display:{Name:"Other"}}`,
+		}
+		for _, s := range paths {
+			tags, err := MustParsePath(s).Get(tag)
+			assert.Equal(t, ErrNoMatch, err, s)
+			assert.Empty(t, tags, s)
+		}
+	})
+}
+
+func TestPath_Set(t *testing.T) {
+	t.Run("should be ok with an existing element", func(t *testing.T) {
+		tag := newPathTag()
+
+		n, err := MustParsePath(`Inventory[{Slot:0b}].tag.display.Name`).Set(tag, &StringT{Value: "Stone"})
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, n)
+			tags, _ := MustParsePath(`Inventory[0].tag.display.Name`).Get(tag)
+			assert.Equal(t, []Tag{&StringT{Name: "Name", Value: "Stone"}}, tags)
+		}
+		n, err = MustParsePath(`Pos[-1]`).Set(tag, &DoubleT{Name: "ignored", Value: 10})
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, n)
+			tags, _ := MustParsePath(`Pos[2]`).Get(tag)
+			assert.Equal(t, []Tag{&DoubleT{Value: 10}}, tags)
+		}
+		n, err = MustParsePath(`Seeds[0]`).Set(tag, &LongT{Value: 7})
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, n)
+			seeds, _ := tag.(*CompoundT).GetLongArray("Seeds")
+			assert.Equal(t, []int64{7, 2, 3}, seeds)
+		}
+	})
+	t.Run("should be ok with several elements and a copy of the value", func(t *testing.T) {
+		tag := newPathTag()
+		value := &CompoundT{}
+		value.SetString("Name", "Stone")
+
+		n, err := MustParsePath(`Inventory[{id:"minecraft:stone"}].tag.display`).Set(tag, value)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 2, n)
+			tags, _ := MustParsePath(`Inventory[].tag.display`).Get(tag)
+			if assert.Len(t, tags, 2) {
+				assert.True(t, tags[0] == value)
+				assert.False(t, tags[1] == value)
+				assert.Equal(t, tags[0], tags[1])
+			}
+		}
+		n, err = MustParsePath(`Inventory[].Count`).Set(tag, &ByteT{Value: 1})
+		if assert.NoError(t, err) {
+			assert.Equal(t, 3, n)
+		}
+	})
+	t.Run("should be ok and create the missing elements", func(t *testing.T) {
+		tag := &CompoundT{}
+
+		n, err := MustParsePath(`a.b[{id:1}].c`).Set(tag, &IntT{Value: 2})
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, n)
+			assert.Equal(t, `{a:{b:[{id:1,c:2}]}}`, compactSNBT(tag))
+		}
+		n, err = MustParsePath(`a.d{x:1b}.e[].f`).Set(tag, &StringT{Value: "g"})
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, n)
+			assert.Equal(t, `{a:{b:[{id:1,c:2}],d:{x:1b,e:[{f:g}]}}}`, compactSNBT(tag))
+		}
+	})
+	t.Run("should return an error because the value doesn't match the list type", func(t *testing.T) {
+		tag := newPathTag()
+
+		n, err := MustParsePath(`Pos[0]`).Set(tag, &StringT{Value: "a"})
+		assert.Equal(t, ErrListType, err)
+		assert.Equal(t, 0, n)
+		n, err = MustParsePath(`Seeds[0]`).Set(tag, &IntT{Value: 1})
+		assert.Equal(t, ErrListType, err)
+		assert.Equal(t, 0, n)
+	})
+	t.Run("should be ok and replace the type of the only element", func(t *testing.T) {
+		tag := &CompoundT{}
+		tag.Set("l", &ListT{ElemType: TagInt, Value: []interface{}{&IntT{Value: 1}}})
+
+		n, err := MustParsePath(`l[0]`).Set(tag, &StringT{Value: "a"})
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, n)
+			list, _ := tag.GetList("l")
+			assert.EqualValues(t, TagString, list.ElemType)
+		}
+	})
+	t.Run("should return an error because nothing matches", func(t *testing.T) {
+		tag := newPathTag()
+
+		_, err := MustParsePath(`Pos[3]`).Set(tag, &DoubleT{})
+		assert.Equal(t, ErrNoMatch, err)
+		_, err = MustParsePath(`Pos[0].x`).Set(tag, &DoubleT{})
+		assert.Equal(t, ErrNoMatch, err)
+		_, err = MustParsePath(`{Missing:1b}`).Set(tag, &DoubleT{})
+		assert.Equal(t, ErrNoMatch, err)
+		_, err = Path{}.Set(tag, &DoubleT{})
+		assert.Equal(t, ErrNoMatch, err)
+	})
+}
+
+func TestPath_Remove(t *testing.T) {
+	t.Run("should be ok with the elements of a compound", func(t *testing.T) {
+		tag := newPathTag()
+
+		n, err := MustParsePath(`Inventory[].tag`).Remove(tag)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, n)
+			_, err = MustParsePath(`Inventory[].tag`).Get(tag)
+			assert.Equal(t, ErrNoMatch, err)
+		}
+		n, err = MustParsePath(`Inventory[].Count{}`).Remove(tag)
+		assert.Equal(t, ErrNoMatch, err)
+		assert.Equal(t, 0, n)
+	})
+	t.Run("should be ok with the elements of a list", func(t *testing.T) {
+		tag := newPathTag()
+
+		n, err := MustParsePath(`Inventory[{id:"minecraft:stone"}]`).Remove(tag)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 2, n)
+			tags, _ := MustParsePath(`Inventory[].Slot`).Get(tag)
+			assert.Equal(t, []Tag{&ByteT{Name: "Slot", Value: 1}}, tags)
+		}
+		n, err = MustParsePath(`Pos[-2]`).Remove(tag)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, n)
+			pos, _ := tag.(*CompoundT).GetList("Pos")
+			assert.Equal(t, []interface{}{&DoubleT{Value: 1.5}, &DoubleT{Value: -3}}, pos.Value)
+		}
+		n, err = MustParsePath(`Pos[]`).Remove(tag)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 2, n)
+			pos, _ := tag.(*CompoundT).GetList("Pos")
+			assert.Equal(t, 0, pos.Len())
+		}
+	})
+	t.Run("should be ok with the elements of an array", func(t *testing.T) {
+		tag := newPathTag()
+		previous, _ := tag.(*CompoundT).GetLongArray("Seeds")
+
+		n, err := MustParsePath(`Seeds[1]`).Remove(tag)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, n)
+			seeds, _ := tag.(*CompoundT).GetLongArray("Seeds")
+			assert.Equal(t, []int64{1, 3}, seeds)
+			// the slice returned before is not modified
+			assert.Equal(t, []int64{1, 2, 3}, previous)
+		}
+	})
+	t.Run("should be ok and keep the data of an aliased byte array", func(t *testing.T) {
+		data, err := Marshal(&ByteArrayT{Value: []byte{1, 2, 3}}, CompressNone)
+		if !assert.NoError(t, err) {
+			return
+		}
+		tag, err := UnmarshalAlias(data)
+		if !assert.NoError(t, err) {
+			return
+		}
+		expected := append([]byte{}, data...)
+
+		n, err := MustParsePath(`[0]`).Remove(tag)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, n)
+			assert.Equal(t, []byte{2, 3}, tag.(*ByteArrayT).Value)
+			assert.Equal(t, expected, data)
+		}
+	})
+	t.Run("should return an error because nothing matches", func(t *testing.T) {
+		tag := newPathTag()
+
+		_, err := MustParsePath(`Inventory[{Slot:9b}]`).Remove(tag)
+		assert.Equal(t, ErrNoMatch, err)
+		_, err = MustParsePath(`Missing.a`).Remove(tag)
+		assert.Equal(t, ErrNoMatch, err)
+		_, err = Path{}.Remove(tag)
+		assert.Equal(t, ErrNoMatch, err)
+	})
+}
@@ -1,11 +1,17 @@
 package gonbt
 
 import (
+	"bytes"
 	"encoding/binary"
+	"errors"
 	"io"
-	"unsafe"
+	"math"
 )
 
+// maxPrealloc is the size in bytes allocated before the data of an array is read,
+// the larger arrays grow while they are read to not trust the length of the data
+const maxPrealloc = 1 << 16
+
 // Reader nbt
 type Reader interface {
 	String() (string, error)
@@ -21,108 +27,172 @@ type Reader interface {
 }
 
 type reader struct {
-	flux io.Reader
+	flux  io.Reader
+	order binary.ByteOrder
+	// mutf8 decode the strings with the java modified UTF-8
+	mutf8 bool
+	// raw keep the bytes of the malformed strings rather than return an error
+	raw bool
+	buf [8]byte
+	limiter
 }
 
-// NewReader nbt
+// NewReader nbt with the java big-endian byte order
 func NewReader(r io.Reader) Reader {
+	return NewReaderOrder(r, binary.BigEndian)
+}
+
+// NewReaderOrder nbt with the byte order, binary.LittleEndian for the bedrock edition.
+// The big-endian strings of the java edition are decoded from the modified UTF-8
+func NewReaderOrder(r io.Reader, order binary.ByteOrder) Reader {
 	return &reader{
-		flux: r,
+		flux:    r,
+		order:   order,
+		mutf8:   order == binary.BigEndian,
+		limiter: limiter{limits: DefaultLimits()},
+	}
+}
+
+// truncated return ErrTruncated for the end of data in the middle of a value
+func truncated(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrTruncated
+	}
+	return err
+}
+
+// read fill the n first bytes of the scratch buffer
+func (r *reader) read(n int) ([]byte, error) {
+	var err error
+
+	if _, err = io.ReadFull(r.flux, r.buf[:n]); err != nil {
+		return nil, truncated(err)
+	}
+	return r.buf[:n], nil
+}
+
+// readFull return the next size bytes of r
+func readFull(r io.Reader, size int64) ([]byte, error) {
+	var err error
+
+	if size <= maxPrealloc {
+		data := make([]byte, size)
+		if _, err = io.ReadFull(r, data); err != nil {
+			return nil, truncated(err)
+		}
+		return data, nil
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, maxPrealloc))
+	if _, err = io.CopyN(buf, r, size); err != nil {
+		return nil, truncated(err)
 	}
+	return buf.Bytes(), nil
 }
 
 // String reader with nbt format
 func (r *reader) String() (string, error) {
-	var size uint16
-	var data []byte
+	var b, data []byte
 	var err error
 
-	bsize := make([]byte, unsafe.Sizeof(size))
-	if _, err = r.flux.Read(bsize); err != nil {
+	if b, err = r.read(2); err != nil {
 		return "", err
 	}
-	size = binary.BigEndian.Uint16(bsize)
+	size := r.order.Uint16(b)
 	if size == 0 {
 		return "", nil
 	}
-	data = make([]byte, size)
-	if _, err = r.flux.Read(data); err != nil {
+	if data, err = readFull(r.flux, int64(size)); err != nil {
 		return "", err
 	}
-	return string(data), nil
+	if !r.mutf8 {
+		return string(data), nil
+	}
+	if str, ok := decodeMUTF8(data); ok {
+		return str, nil
+	}
+	if r.raw {
+		return string(data), nil
+	}
+	return "", errors.New(errorMUTF8)
 }
 
-// Byte reader with nbt format
+// Byte reader with nbt format, it return io.EOF at the end of data
 func (r *reader) Byte() (byte, error) {
-	var size uint8
-	var data []byte
 	var err error
 
-	data = make([]byte, unsafe.Sizeof(size))
-	if _, err = r.flux.Read(data); err != nil {
+	if _, err = io.ReadFull(r.flux, r.buf[:1]); err != nil {
 		return byte('0'), err
 	}
-	return data[0], nil
+	return r.buf[0], nil
 }
 
 // Short reader with nbt format
 func (r *reader) Short() (int16, error) {
-	var size int16
+	var b []byte
 	var err error
 
-	b := make([]byte, unsafe.Sizeof(size))
-	if _, err = r.flux.Read(b); err != nil {
+	if b, err = r.read(2); err != nil {
 		return int16(0), err
 	}
-	return int16(binary.BigEndian.Uint16(b)), nil
+	return int16(r.order.Uint16(b)), nil
 }
 
 // Int reader with nbt format
 func (r *reader) Int() (int32, error) {
-	var size int32
+	var b []byte
 	var err error
 
-	b := make([]byte, unsafe.Sizeof(size))
-	if _, err = r.flux.Read(b); err != nil {
+	if b, err = r.read(4); err != nil {
 		return int32(0), err
 	}
-	return int32(binary.BigEndian.Uint32(b)), nil
+	return int32(r.order.Uint32(b)), nil
 }
 
 // Long reader with nbt format
 func (r *reader) Long() (int64, error) {
-	var size int64
+	var b []byte
 	var err error
 
-	b := make([]byte, unsafe.Sizeof(size))
-	if _, err = r.flux.Read(b); err != nil {
+	if b, err = r.read(8); err != nil {
 		return int64(0), err
 	}
-	return int64(binary.BigEndian.Uint64(b)), nil
+	return int64(r.order.Uint64(b)), nil
 }
 
 // Float reader with nbt format
 func (r *reader) Float() (float32, error) {
-	var size float32
+	var b []byte
 	var err error
 
-	b := make([]byte, unsafe.Sizeof(size))
-	if _, err = r.flux.Read(b); err != nil {
+	if b, err = r.read(4); err != nil {
 		return float32(0.0), err
 	}
-	return float32(binary.BigEndian.Uint32(b)), nil
+	return math.Float32frombits(r.order.Uint32(b)), nil
 }
 
 // Double reader with nbt format
 func (r *reader) Double() (float64, error) {
-	var size float64
+	var b []byte
 	var err error
 
-	b := make([]byte, unsafe.Sizeof(size))
-	if _, err = r.flux.Read(b); err != nil {
+	if b, err = r.read(8); err != nil {
 		return float64(0), err
 	}
-	return float64(binary.BigEndian.Uint64(b)), nil
+	return math.Float64frombits(r.order.Uint64(b)), nil
+}
+
+// arrayLength read and check the length of an array
+func (r *reader) arrayLength() (int32, error) {
+	var size int32
+	var err error
+
+	if size, err = r.Int(); err != nil {
+		return 0, err
+	}
+	if err = r.length(size); err != nil {
+		return 0, err
+	}
+	return size, nil
 }
 
 // Bytes reader with nbt format
@@ -131,17 +201,10 @@ func (r *reader) Bytes() ([]byte, error) {
 	var data []byte
 	var err error
 
-	bsize := make([]byte, unsafe.Sizeof(size))
-	if _, err = r.flux.Read(bsize); err != nil {
+	if size, err = r.arrayLength(); err != nil {
 		return []byte{}, err
 	}
-
-	size = int32(binary.BigEndian.Uint32(bsize))
-	if size == 0 {
-		return []byte{}, nil
-	}
-	data = make([]byte, size)
-	if _, err = r.flux.Read(data); err != nil {
+	if data, err = readFull(r.flux, int64(size)); err != nil {
 		return []byte{}, err
 	}
 	return data, nil
@@ -149,52 +212,38 @@ func (r *reader) Bytes() ([]byte, error) {
 
 // IntArray reader with nbt format
 func (r *reader) IntArray() ([]int32, error) {
+	var size int32
+	var data []byte
 	var err error
-	var ret []int32
-	var nbr int32
 
-	// get number element
-	b := make([]byte, unsafe.Sizeof(nbr))
-	if _, err = r.flux.Read(b); err != nil {
+	if size, err = r.arrayLength(); err != nil {
 		return []int32{}, err
 	}
-	nbr = int32(binary.BigEndian.Uint32(b))
-
-	for i := int32(0); i < nbr; i++ {
-		var elem int32
-
-		b := make([]byte, unsafe.Sizeof(elem))
-		if _, err = r.flux.Read(b); err != nil {
-			return []int32{}, err
-		}
-		elem = int32(binary.BigEndian.Uint32(b))
-		ret = append(ret, elem)
+	if data, err = readFull(r.flux, int64(size)*4); err != nil {
+		return []int32{}, err
+	}
+	ret := make([]int32, size)
+	for i := range ret {
+		ret[i] = int32(r.order.Uint32(data[i*4:]))
 	}
 	return ret, nil
 }
 
 // LongArray reader with nbt format
 func (r *reader) LongArray() ([]int64, error) {
+	var size int32
+	var data []byte
 	var err error
-	var ret []int64
-	var nbr int64
 
-	// get number element
-	b := make([]byte, unsafe.Sizeof(nbr))
-	if _, err = r.flux.Read(b); err != nil {
+	if size, err = r.arrayLength(); err != nil {
 		return []int64{}, err
 	}
-	nbr = int64(binary.BigEndian.Uint64(b))
-
-	for i := int64(0); i < nbr; i++ {
-		var elem int64
-
-		b := make([]byte, unsafe.Sizeof(elem))
-		if _, err = r.flux.Read(b); err != nil {
-			return []int64{}, err
-		}
-		elem = int64(binary.BigEndian.Uint64(b))
-		ret = append(ret, elem)
+	if data, err = readFull(r.flux, int64(size)*8); err != nil {
+		return []int64{}, err
+	}
+	ret := make([]int64, size)
+	for i := range ret {
+		ret[i] = int64(r.order.Uint64(data[i*8:]))
 	}
 	return ret, nil
 }
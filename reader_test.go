@@ -2,6 +2,9 @@ package gonbt
 
 import (
 	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,9 +13,9 @@ import (
 func TestReader_String(t *testing.T) {
 	t.Run("read return an error because reader is empty", func(t *testing.T) {
 		data := []byte{}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
-		expectedError := "EOF"
+		expectedError := errorTruncated
 		str, err := r.String()
 		if assert.Error(t, err) {
 			assert.EqualValues(t, expectedError, err.Error())
@@ -21,9 +24,9 @@ func TestReader_String(t *testing.T) {
 	})
 	t.Run("read return an error because reader is empty after the bsize loaded", func(t *testing.T) {
 		data := []byte{0x00, 0x0a}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
-		expectedError := "EOF"
+		expectedError := errorTruncated
 		str, err := r.String()
 		if assert.Error(t, err) {
 			assert.EqualValues(t, expectedError, err.Error())
@@ -32,7 +35,7 @@ func TestReader_String(t *testing.T) {
 	})
 	t.Run("Should be ok with an empty string", func(t *testing.T) {
 		data := []byte{0x00, 0x00}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
 		str, err := r.String()
 		if assert.NoError(t, err) {
@@ -41,7 +44,7 @@ func TestReader_String(t *testing.T) {
 	})
 	t.Run("Should be", func(t *testing.T) {
 		data := append([]byte{0x00, 0x0a}, []byte("Hello YOU!")...)
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
 		expectedSTR := "Hello YOU!"
 		str, err := r.String()
@@ -51,10 +54,23 @@ func TestReader_String(t *testing.T) {
 	})
 }
 
+// shortReader return at most n bytes on each Read like a network stream
+type shortReader struct {
+	r io.Reader
+	n int
+}
+
+func (s shortReader) Read(p []byte) (int, error) {
+	if len(p) > s.n {
+		p = p[:s.n]
+	}
+	return s.r.Read(p)
+}
+
 func TestReader_Byte(t *testing.T) {
 	t.Run("should return an error because the data is empty", func(t *testing.T) {
 		data := []byte{}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
 		expectedError := "EOF"
 		expectedByte := byte('0')
@@ -66,7 +82,7 @@ func TestReader_Byte(t *testing.T) {
 	})
 	t.Run("should be ok", func(t *testing.T) {
 		data := []byte{0x0a}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
 		expectedByte := byte(10)
 		ret, err := r.Byte()
@@ -79,9 +95,9 @@ func TestReader_Byte(t *testing.T) {
 func TestReader_Short(t *testing.T) {
 	t.Run("should return an error because the data is empty", func(t *testing.T) {
 		data := []byte{}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
-		expectedError := "EOF"
+		expectedError := errorTruncated
 		expectedRet := int16(0)
 		ret, err := r.Short()
 		if assert.Error(t, err) {
@@ -91,7 +107,7 @@ func TestReader_Short(t *testing.T) {
 	})
 	t.Run("should be ok", func(t *testing.T) {
 		data := []byte{0x00, 0x0a}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
 		expectedRet := int16(10)
 		ret, err := r.Short()
@@ -104,9 +120,9 @@ func TestReader_Short(t *testing.T) {
 func TestReader_Int(t *testing.T) {
 	t.Run("should return an error because the data is empty", func(t *testing.T) {
 		data := []byte{}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
-		expectedError := "EOF"
+		expectedError := errorTruncated
 		expectedRet := int32(0)
 		ret, err := r.Int()
 		if assert.Error(t, err) {
@@ -116,7 +132,7 @@ func TestReader_Int(t *testing.T) {
 	})
 	t.Run("should be ok", func(t *testing.T) {
 		data := []byte{0x0a, 0x0a, 0x00, 0x0a}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
 		expectedRet := int32(168427530)
 		ret, err := r.Int()
@@ -129,9 +145,9 @@ func TestReader_Int(t *testing.T) {
 func TestReader_Long(t *testing.T) {
 	t.Run("should return an error because the data is empty", func(t *testing.T) {
 		data := []byte{}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
-		expectedError := "EOF"
+		expectedError := errorTruncated
 		expectedRet := int64(0)
 		ret, err := r.Long()
 		if assert.Error(t, err) {
@@ -141,7 +157,7 @@ func TestReader_Long(t *testing.T) {
 	})
 	t.Run("should be ok", func(t *testing.T) {
 		data := []byte{0x00, 0x00, 0x00, 0x00, 0x0a, 0x0a, 0x00, 0x0a}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
 		expectedRet := int64(168427530)
 		ret, err := r.Long()
@@ -154,9 +170,9 @@ func TestReader_Long(t *testing.T) {
 func TestReader_Float(t *testing.T) {
 	t.Run("should return an error because the data is empty", func(t *testing.T) {
 		data := []byte{}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
-		expectedError := "EOF"
+		expectedError := errorTruncated
 		expectedRet := float32(0)
 		ret, err := r.Float()
 		if assert.Error(t, err) {
@@ -165,10 +181,10 @@ func TestReader_Float(t *testing.T) {
 		}
 	})
 	t.Run("should be ok", func(t *testing.T) {
-		data := []byte{0x0a, 0x0a, 0x00, 0x0a}
-		r := &reader{flux: bytes.NewReader(data)}
+		data := []byte{0x3f, 0xc0, 0x00, 0x00}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
-		expectedRet := float32(168427530)
+		expectedRet := float32(1.5)
 		ret, err := r.Float()
 		if assert.NoError(t, err) {
 			assert.EqualValues(t, expectedRet, ret)
@@ -179,9 +195,9 @@ func TestReader_Float(t *testing.T) {
 func TestReader_Double(t *testing.T) {
 	t.Run("should return an error because the data is empty", func(t *testing.T) {
 		data := []byte{}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
-		expectedError := "EOF"
+		expectedError := errorTruncated
 		expectedRet := float64(0)
 		ret, err := r.Double()
 		if assert.Error(t, err) {
@@ -190,10 +206,10 @@ func TestReader_Double(t *testing.T) {
 		}
 	})
 	t.Run("should be ok", func(t *testing.T) {
-		data := []byte{0x00, 0x00, 0x00, 0x00, 0x0a, 0x0a, 0x00, 0x0a}
-		r := &reader{flux: bytes.NewReader(data)}
+		data := []byte{0xc0, 0x50, 0x40, 0x00, 0x00, 0x00, 0x00, 0x00}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
-		expectedRet := float64(168427530)
+		expectedRet := float64(-65)
 		ret, err := r.Double()
 		if assert.NoError(t, err) {
 			assert.EqualValues(t, expectedRet, ret)
@@ -204,9 +220,9 @@ func TestReader_Double(t *testing.T) {
 func TestReader_Bytes(t *testing.T) {
 	t.Run("read return an error because reader is empty", func(t *testing.T) {
 		data := []byte{}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
-		expectedError := "EOF"
+		expectedError := errorTruncated
 		ret, err := r.Bytes()
 		if assert.Error(t, err) {
 			assert.EqualValues(t, expectedError, err.Error())
@@ -215,9 +231,9 @@ func TestReader_Bytes(t *testing.T) {
 	})
 	t.Run("read return an error because reader is empty after the bsize loaded", func(t *testing.T) {
 		data := []byte{0x00, 0x00, 0x00, 0x0a}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
-		expectedError := "EOF"
+		expectedError := errorTruncated
 		ret, err := r.Bytes()
 		if assert.Error(t, err) {
 			assert.EqualValues(t, expectedError, err.Error())
@@ -226,7 +242,7 @@ func TestReader_Bytes(t *testing.T) {
 	})
 	t.Run("Should be ok with an empty string", func(t *testing.T) {
 		data := []byte{0x00, 0x00, 0x00, 0x00}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
 		ret, err := r.Bytes()
 		if assert.NoError(t, err) {
@@ -235,7 +251,7 @@ func TestReader_Bytes(t *testing.T) {
 	})
 	t.Run("Should be", func(t *testing.T) {
 		data := append([]byte{0x00, 0x00, 0x00, 0x0a}, []byte("Hello YOU!")...)
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
 		expectedRet := []byte("Hello YOU!")
 		ret, err := r.Bytes()
@@ -248,9 +264,9 @@ func TestReader_Bytes(t *testing.T) {
 func TestReader_IntArray(t *testing.T) {
 	t.Run("should return an error because flux is empty", func(t *testing.T) {
 		data := []byte{}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
-		expectedError := "EOF"
+		expectedError := errorTruncated
 		ret, err := r.IntArray()
 		if assert.Error(t, err) {
 			assert.EqualValues(t, expectedError, err.Error())
@@ -259,9 +275,9 @@ func TestReader_IntArray(t *testing.T) {
 	})
 	t.Run("should return an error because the flux is corrompted", func(t *testing.T) {
 		data := []byte{0x00, 0x00, 0x00, 0x0a}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
-		expectedError := "EOF"
+		expectedError := errorTruncated
 		ret, err := r.IntArray()
 		if assert.Error(t, err) {
 			assert.EqualValues(t, expectedError, err.Error())
@@ -270,7 +286,7 @@ func TestReader_IntArray(t *testing.T) {
 	})
 	t.Run("should be ok with an empty list", func(t *testing.T) {
 		data := []byte{0x00, 0x00, 0x00, 0x00}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
 		ret, err := r.IntArray()
 		if assert.NoError(t, err) {
@@ -279,7 +295,7 @@ func TestReader_IntArray(t *testing.T) {
 	})
 	t.Run("should be", func(t *testing.T) {
 		data := []byte{0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x0b, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x00, 0x00, 0x2a}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
 		expectedRet := []int32{11, 10, 42}
 		ret, err := r.IntArray()
@@ -292,9 +308,9 @@ func TestReader_IntArray(t *testing.T) {
 func TestReader_LongArray(t *testing.T) {
 	t.Run("should return an error because flux is empty", func(t *testing.T) {
 		data := []byte{}
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
-		expectedError := "EOF"
+		expectedError := errorTruncated
 		ret, err := r.LongArray()
 		if assert.Error(t, err) {
 			assert.EqualValues(t, expectedError, err.Error())
@@ -302,10 +318,10 @@ func TestReader_LongArray(t *testing.T) {
 		}
 	})
 	t.Run("should return an error because the flux is corrompted", func(t *testing.T) {
-		data := []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a}
-		r := &reader{flux: bytes.NewReader(data)}
+		data := []byte{0x00, 0x00, 0x00, 0x0a}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
-		expectedError := "EOF"
+		expectedError := errorTruncated
 		ret, err := r.LongArray()
 		if assert.Error(t, err) {
 			assert.EqualValues(t, expectedError, err.Error())
@@ -313,8 +329,8 @@ func TestReader_LongArray(t *testing.T) {
 		}
 	})
 	t.Run("should be ok with an empty list", func(t *testing.T) {
-		data := []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
-		r := &reader{flux: bytes.NewReader(data)}
+		data := []byte{0x00, 0x00, 0x00, 0x00}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
 		ret, err := r.LongArray()
 		if assert.NoError(t, err) {
@@ -322,11 +338,11 @@ func TestReader_LongArray(t *testing.T) {
 		}
 	})
 	t.Run("should be", func(t *testing.T) {
-		data := []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03}
+		data := []byte{0x00, 0x00, 0x00, 0x03}
 		data = append(data, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0b}...)
 		data = append(data, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a}...)
 		data = append(data, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x2a}...)
-		r := &reader{flux: bytes.NewReader(data)}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
 
 		expectedRet := []int64{11, 10, 42}
 		ret, err := r.LongArray()
@@ -335,3 +351,57 @@ func TestReader_LongArray(t *testing.T) {
 		}
 	})
 }
+
+func TestReader_ShortReads(t *testing.T) {
+	t.Run("should be ok with a stream which return less data than requested", func(t *testing.T) {
+		data := []byte{0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0b, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a}
+		r := &reader{flux: shortReader{r: bytes.NewReader(data), n: 3}, order: binary.BigEndian}
+
+		ret, err := r.LongArray()
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, []int64{11, 10}, ret)
+		}
+	})
+	t.Run("should return an error because a large array is truncated", func(t *testing.T) {
+		data := []byte{0x00, 0x10, 0x00, 0x00, 0x01, 0x02}
+		r := &reader{flux: bytes.NewReader(data), order: binary.BigEndian}
+
+		ret, err := r.Bytes()
+		if assert.Error(t, err) {
+			assert.Equal(t, ErrTruncated, err)
+			assert.Empty(t, ret)
+		}
+	})
+}
+
+func TestReader_FloatRoundTrip(t *testing.T) {
+	floats := []float32{0, float32(math.Copysign(0, -1)), 1.5, -65, math.MaxFloat32, math.SmallestNonzeroFloat32,
+		float32(math.Inf(1)), float32(math.Inf(-1)), float32(math.NaN()), math.Float32frombits(0x7fc00001)}
+	doubles := []float64{0, math.Copysign(0, -1), 0.1, -65, math.MaxFloat64, math.SmallestNonzeroFloat64,
+		math.Inf(1), math.Inf(-1), math.NaN(), math.Float64frombits(0x7ff8000000000001)}
+
+	for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		var buf bytes.Buffer
+		w := NewWriterOrder(&buf, order)
+		for _, f := range floats {
+			assert.NoError(t, w.Float(f))
+		}
+		for _, d := range doubles {
+			assert.NoError(t, w.Double(d))
+		}
+
+		r := NewReaderOrder(&buf, order)
+		for _, f := range floats {
+			ret, err := r.Float()
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, math.Float32bits(f), math.Float32bits(ret))
+			}
+		}
+		for _, d := range doubles {
+			ret, err := r.Double()
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, math.Float64bits(d), math.Float64bits(ret))
+			}
+		}
+	}
+}
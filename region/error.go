@@ -0,0 +1,52 @@
+package region
+
+import (
+	"errors"
+	"strconv"
+)
+
+// errors description
+const (
+	errorLocation = "chunk location out of the file"
+	errorLength   = "chunk length out of the sectors"
+	errorExternal = "external chunk without region coordinates"
+)
+
+// errors list
+var (
+	// ErrChunkNotFound is returned for the chunks which are not stored in the region
+	ErrChunkNotFound = errors.New("chunk not found")
+	// ErrCorrupt is returned when the region or the chunk data are invalid
+	ErrCorrupt = errors.New("corrupted data")
+	// ErrCompression is returned for the unsupported compression types
+	ErrCompression = errors.New("compression type unsupported")
+	// ErrCoordinates is returned for the chunk coordinates out of the region
+	ErrCoordinates = errors.New("chunk coordinates out of the region")
+	// ErrReadOnly is returned when a chunk is written in a region opened without write access
+	ErrReadOnly = errors.New("region opened in read only")
+	// ErrChunkTooLarge is returned when a chunk too large for the region can't be stored in an external file
+	ErrChunkTooLarge = errors.New("chunk too large")
+)
+
+// ChunkError describe a chunk which can't be read
+type ChunkError struct {
+	// X and Z are the local coordinates of the chunk
+	X, Z int
+	// Err is ErrChunkNotFound, ErrCorrupt, ErrCompression or ErrCoordinates
+	Err error
+	// Cause is the underlying error when there is one
+	Cause error
+}
+
+func (e *ChunkError) Error() string {
+	msg := "chunk " + strconv.Itoa(e.X) + ", " + strconv.Itoa(e.Z) + ": " + e.Err.Error()
+	if e.Cause != nil {
+		msg += ": " + e.Cause.Error()
+	}
+	return msg
+}
+
+// Unwrap return Err to use errors.Is with the errors list
+func (e *ChunkError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,235 @@
+// Package region read and write the anvil region files (r.<x>.<z>.mca) which store the
+// chunks of a minecraft world by group of 32x32 chunks.
+// https://minecraft.fandom.com/wiki/Region_file_format
+package region
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ymohl-cl/gonbt"
+)
+
+// region file layout
+const (
+	// SectorSize is the size of the file blocks
+	SectorSize = 4096
+	// Width is the number of chunks by side of a region
+	Width = 32
+	// ChunkCount is the number of chunks in a region
+	ChunkCount = Width * Width
+	// headerSize is the size of the location and timestamp tables
+	headerSize = 2 * SectorSize
+	// chunkHeaderSize is the size of the chunk length and compression type
+	chunkHeaderSize = 5
+)
+
+// compression types of the chunks
+const (
+	CompressionGZIP byte = 1
+	CompressionZLIB byte = 2
+	CompressionNone byte = 3
+	// externalFlag is set on the compression type when the chunk is stored in a c.<x>.<z>.mcc file
+	externalFlag byte = 0x80
+)
+
+// File is a region file
+type File struct {
+	r      io.ReaderAt
+	w      io.WriterAt
+	size   int64
+	closer io.Closer
+	// dir and the region coordinates to find the external chunks
+	dir      string
+	x, z     int
+	hasCoord bool
+
+	locations  [ChunkCount]uint32
+	timestamps [ChunkCount]uint32
+}
+
+// Open the region file name in read only
+func Open(name string) (*File, error) {
+	return OpenFile(name, os.O_RDONLY, 0)
+}
+
+// New read the region of size bytes from r
+func New(r io.ReaderAt, size int64) (*File, error) {
+	f := &File{r: r, size: size}
+
+	// an empty file is a region without chunk
+	if size == 0 {
+		return f, nil
+	}
+	if size < headerSize {
+		return nil, ErrCorrupt
+	}
+	header := make([]byte, headerSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, err
+	}
+	for i := 0; i < ChunkCount; i++ {
+		f.locations[i] = binary.BigEndian.Uint32(header[i*4:])
+		f.timestamps[i] = binary.BigEndian.Uint32(header[SectorSize+i*4:])
+	}
+	return f, nil
+}
+
+// ParseName return the region coordinates of a file named r.<x>.<z>.mca
+func ParseName(name string) (int, int, bool) {
+	var err error
+	var x, z int
+
+	parts := strings.Split(name, ".")
+	if len(parts) != 4 || parts[0] != "r" || parts[3] != "mca" {
+		return 0, 0, false
+	}
+	if x, err = strconv.Atoi(parts[1]); err != nil {
+		return 0, 0, false
+	}
+	if z, err = strconv.Atoi(parts[2]); err != nil {
+		return 0, 0, false
+	}
+	return x, z, true
+}
+
+// Close the region file when it's opened by Open
+func (f *File) Close() error {
+	if f.closer == nil {
+		return nil
+	}
+	return f.closer.Close()
+}
+
+// index return the index of the chunk in the tables
+func index(x, z int) (int, error) {
+	if x < 0 || x >= Width || z < 0 || z >= Width {
+		return 0, &ChunkError{X: x, Z: z, Err: ErrCoordinates}
+	}
+	return x + z*Width, nil
+}
+
+// HasChunk report if the chunk at the local coordinates x, z is stored in the region
+func (f *File) HasChunk(x, z int) bool {
+	i, err := index(x, z)
+	return err == nil && f.locations[i] != 0
+}
+
+// Timestamp return the last modification time of the chunk at the local coordinates x, z
+func (f *File) Timestamp(x, z int) (time.Time, error) {
+	var err error
+	var i int
+
+	if i, err = index(x, z); err != nil {
+		return time.Time{}, err
+	}
+	if f.locations[i] == 0 {
+		return time.Time{}, &ChunkError{X: x, Z: z, Err: ErrChunkNotFound}
+	}
+	return time.Unix(int64(f.timestamps[i]), 0), nil
+}
+
+// ReadChunk return the tag of the chunk at the local coordinates x, z
+func (f *File) ReadChunk(x, z int) (gonbt.Tag, error) {
+	var err error
+	var compression byte
+	var data []byte
+	var tag gonbt.Tag
+
+	if compression, data, err = f.chunkData(x, z); err != nil {
+		return nil, err
+	}
+	if data, err = decompress(compression, data); err != nil {
+		if err == ErrCompression {
+			return nil, &ChunkError{X: x, Z: z, Err: ErrCompression}
+		}
+		return nil, &ChunkError{X: x, Z: z, Err: ErrCorrupt, Cause: err}
+	}
+	if tag, err = gonbt.Unmarshal(data); err != nil {
+		return nil, &ChunkError{X: x, Z: z, Err: ErrCorrupt, Cause: err}
+	}
+	return tag, nil
+}
+
+// chunkData return the compression type and the compressed data of the chunk
+func (f *File) chunkData(x, z int) (byte, []byte, error) {
+	var err error
+	var i int
+
+	if i, err = index(x, z); err != nil {
+		return 0, nil, err
+	}
+	location := f.locations[i]
+	if location == 0 {
+		return 0, nil, &ChunkError{X: x, Z: z, Err: ErrChunkNotFound}
+	}
+	corrupt := func(cause error) (byte, []byte, error) {
+		return 0, nil, &ChunkError{X: x, Z: z, Err: ErrCorrupt, Cause: cause}
+	}
+
+	offset := int64(location>>8) * SectorSize
+	size := int64(location&0xff) * SectorSize
+	if offset < headerSize || size < chunkHeaderSize || offset+size > f.size {
+		return corrupt(errors.New(errorLocation))
+	}
+	header := make([]byte, chunkHeaderSize)
+	if _, err = f.r.ReadAt(header, offset); err != nil {
+		return corrupt(err)
+	}
+	length := int64(binary.BigEndian.Uint32(header))
+	compression := header[4]
+	if length < 1 || length+4 > size {
+		return corrupt(errors.New(errorLength))
+	}
+
+	if compression&externalFlag != 0 {
+		var data []byte
+
+		if !f.hasCoord {
+			return corrupt(errors.New(errorExternal))
+		}
+		name := "c." + strconv.Itoa(f.x*Width+x) + "." + strconv.Itoa(f.z*Width+z) + ".mcc"
+		if data, err = ioutil.ReadFile(filepath.Join(f.dir, name)); err != nil {
+			return corrupt(err)
+		}
+		return compression &^ externalFlag, data, nil
+	}
+	data := make([]byte, length-1)
+	if _, err = f.r.ReadAt(data, offset+chunkHeaderSize); err != nil {
+		return corrupt(err)
+	}
+	return compression, data, nil
+}
+
+// decompress the chunk data
+func decompress(compression byte, data []byte) ([]byte, error) {
+	var err error
+	var r io.ReadCloser
+
+	switch compression {
+	case CompressionGZIP:
+		if r, err = gzip.NewReader(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+	case CompressionZLIB:
+		if r, err = zlib.NewReader(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+	case CompressionNone:
+		return data, nil
+	default:
+		return nil, ErrCompression
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
@@ -0,0 +1,229 @@
+package region
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymohl-cl/gonbt"
+)
+
+// testChunk describe a chunk to build a region in the tests
+type testChunk struct {
+	x, z        int
+	compression byte
+	data        []byte
+	timestamp   uint32
+	// location overwrite the computed location when it's set
+	location uint32
+}
+
+// newTestChunkTag return a chunk tag with the position x, z
+func newTestChunkTag(x, z int32) *gonbt.CompoundT {
+	level := &gonbt.CompoundT{}
+	level.Set("xPos", &gonbt.IntT{Value: x})
+	level.Set("zPos", &gonbt.IntT{Value: z})
+	level.Set("BlockStates", &gonbt.LongArrayT{Value: []int64{1, -1, 1 << 40}})
+	chunk := &gonbt.CompoundT{}
+	chunk.Set("Level", level)
+	return chunk
+}
+
+// compress the tag t with the compression type
+func compress(t *testing.T, tag gonbt.Tag, compression byte) []byte {
+	var buf bytes.Buffer
+
+	data, err := gonbt.Marshal(tag, gonbt.CompressNone)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	switch compression {
+	case CompressionGZIP:
+		w := gzip.NewWriter(&buf)
+		w.Write(data)
+		w.Close()
+	case CompressionZLIB:
+		w := zlib.NewWriter(&buf)
+		w.Write(data)
+		w.Close()
+	default:
+		return data
+	}
+	return buf.Bytes()
+}
+
+// buildRegion return the region file with the chunks
+func buildRegion(chunks []testChunk) []byte {
+	region := make([]byte, headerSize)
+
+	for _, c := range chunks {
+		i := c.x + c.z*Width
+		payload := make([]byte, chunkHeaderSize, chunkHeaderSize+len(c.data))
+		binary.BigEndian.PutUint32(payload, uint32(len(c.data)+1))
+		payload[4] = c.compression
+		payload = append(payload, c.data...)
+		sectors := (len(payload) + SectorSize - 1) / SectorSize
+		location := uint32(len(region)/SectorSize)<<8 | uint32(sectors)
+		if c.location != 0 {
+			location = c.location
+		}
+		binary.BigEndian.PutUint32(region[i*4:], location)
+		binary.BigEndian.PutUint32(region[SectorSize+i*4:], c.timestamp)
+		region = append(region, payload...)
+		region = append(region, make([]byte, sectors*SectorSize-len(payload))...)
+	}
+	return region
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should return an error because the header is truncated", func(t *testing.T) {
+		data := make([]byte, SectorSize)
+
+		f, err := New(bytes.NewReader(data), int64(len(data)))
+		if assert.Error(t, err) {
+			assert.True(t, errors.Is(err, ErrCorrupt))
+			assert.Nil(t, f)
+		}
+	})
+	t.Run("should be ok with an empty file", func(t *testing.T) {
+		f, err := New(bytes.NewReader([]byte{}), 0)
+		if assert.NoError(t, err) {
+			assert.False(t, f.HasChunk(0, 0))
+			_, err = f.ReadChunk(0, 0)
+			assert.True(t, errors.Is(err, ErrChunkNotFound))
+		}
+	})
+}
+
+func TestFile_ReadChunk(t *testing.T) {
+	tag := newTestChunkTag(3, 4)
+	data := buildRegion([]testChunk{
+		{x: 0, z: 0, compression: CompressionGZIP, data: compress(t, tag, CompressionGZIP), timestamp: 1600000000},
+		{x: 1, z: 2, compression: CompressionZLIB, data: compress(t, tag, CompressionZLIB)},
+		{x: 31, z: 31, compression: CompressionNone, data: compress(t, tag, CompressionNone)},
+		{x: 2, z: 0, compression: 4, data: []byte{0x00}},
+		{x: 3, z: 0, compression: CompressionZLIB, data: []byte("not zlib")},
+		{x: 4, z: 0, compression: CompressionNone, data: []byte{0x00}, location: 0xff01},
+		{x: 5, z: 0, compression: CompressionNone | externalFlag},
+	})
+	f, err := New(bytes.NewReader(data), int64(len(data)))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	t.Run("should return an error because the coordinates are out of the region", func(t *testing.T) {
+		chunk, err := f.ReadChunk(32, 0)
+		if assert.Error(t, err) {
+			assert.True(t, errors.Is(err, ErrCoordinates))
+			assert.Nil(t, chunk)
+		}
+	})
+	t.Run("should return an error because the chunk is absent", func(t *testing.T) {
+		chunk, err := f.ReadChunk(0, 1)
+		if assert.Error(t, err) {
+			assert.True(t, errors.Is(err, ErrChunkNotFound))
+			assert.False(t, errors.Is(err, ErrCorrupt))
+			assert.EqualValues(t, "chunk 0, 1: chunk not found", err.Error())
+			assert.Nil(t, chunk)
+		}
+	})
+	t.Run("should return an error because the compression is unsupported", func(t *testing.T) {
+		_, err := f.ReadChunk(2, 0)
+		if assert.Error(t, err) {
+			assert.True(t, errors.Is(err, ErrCompression))
+		}
+	})
+	t.Run("should return an error because the data is corrupted", func(t *testing.T) {
+		_, err := f.ReadChunk(3, 0)
+		var chunkErr *ChunkError
+		if assert.True(t, errors.As(err, &chunkErr)) {
+			assert.True(t, errors.Is(err, ErrCorrupt))
+			assert.EqualValues(t, 3, chunkErr.X)
+			assert.Error(t, chunkErr.Cause)
+		}
+	})
+	t.Run("should return an error because the location is out of the file", func(t *testing.T) {
+		_, err := f.ReadChunk(4, 0)
+		if assert.Error(t, err) {
+			assert.True(t, errors.Is(err, ErrCorrupt))
+			assert.EqualValues(t, "chunk 4, 0: corrupted data: "+errorLocation, err.Error())
+		}
+	})
+	t.Run("should return an error because the external chunk has no region coordinates", func(t *testing.T) {
+		_, err := f.ReadChunk(5, 0)
+		if assert.Error(t, err) {
+			assert.True(t, errors.Is(err, ErrCorrupt))
+		}
+	})
+	t.Run("should be ok with all the compression types", func(t *testing.T) {
+		for _, coord := range [][2]int{{0, 0}, {1, 2}, {31, 31}} {
+			assert.True(t, f.HasChunk(coord[0], coord[1]))
+			chunk, err := f.ReadChunk(coord[0], coord[1])
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, tag, chunk)
+			}
+		}
+	})
+	t.Run("should be ok with the timestamp", func(t *testing.T) {
+		timestamp, err := f.Timestamp(0, 0)
+		if assert.NoError(t, err) {
+			assert.True(t, time.Unix(1600000000, 0).Equal(timestamp))
+		}
+	})
+}
+
+func TestOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "region")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	t.Run("should return an error because the file doesn't exist", func(t *testing.T) {
+		f, err := Open(filepath.Join(dir, "r.0.0.mca"))
+		if assert.Error(t, err) {
+			assert.Nil(t, f)
+		}
+	})
+	t.Run("should be ok with an external chunk", func(t *testing.T) {
+		tag := newTestChunkTag(-29, 68)
+		data := buildRegion([]testChunk{{x: 3, z: 4, compression: CompressionZLIB | externalFlag}})
+		name := filepath.Join(dir, "r.-1.2.mca")
+		external := filepath.Join(dir, "c.-29.68.mcc")
+		if !assert.NoError(t, ioutil.WriteFile(name, data, 0644)) ||
+			!assert.NoError(t, ioutil.WriteFile(external, compress(t, tag, CompressionZLIB), 0644)) {
+			return
+		}
+
+		f, err := Open(name)
+		if assert.NoError(t, err) {
+			defer f.Close()
+			chunk, err := f.ReadChunk(3, 4)
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, tag, chunk)
+			}
+		}
+	})
+}
+
+func TestParseName(t *testing.T) {
+	t.Run("should return false because the name is not a region", func(t *testing.T) {
+		_, _, ok := ParseName("level.dat")
+		assert.False(t, ok)
+	})
+	t.Run("should be ok", func(t *testing.T) {
+		x, z, ok := ParseName("r.-1.12.mca")
+		if assert.True(t, ok) {
+			assert.EqualValues(t, -1, x)
+			assert.EqualValues(t, 12, z)
+		}
+	})
+}
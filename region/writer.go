@@ -0,0 +1,302 @@
+package region
+
+import (
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ymohl-cl/gonbt"
+)
+
+// maxSectors is the maximum number of sectors of a chunk stored in the region file,
+// the bigger chunks are stored in a c.<x>.<z>.mcc file
+const maxSectors = 0xff
+
+// ReadWriterAt is the storage of a writable region
+type ReadWriterAt interface {
+	io.ReaderAt
+	io.WriterAt
+}
+
+// syncer is implemented by the storages which can flush the written data, like os.File
+type syncer interface {
+	Sync() error
+}
+
+// OpenFile open the region file name with the flag and the perm like os.OpenFile,
+// the region is writable with the flag os.O_RDWR
+func OpenFile(name string, flag int, perm os.FileMode) (*File, error) {
+	var err error
+	var f *os.File
+	var info os.FileInfo
+	var region *File
+
+	if f, err = os.OpenFile(name, flag, perm); err != nil {
+		return nil, err
+	}
+	if info, err = f.Stat(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if flag&os.O_RDWR != 0 {
+		region, err = NewWritable(f, info.Size())
+	} else {
+		region, err = New(f, info.Size())
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	region.closer = f
+	region.dir = filepath.Dir(name)
+	region.x, region.z, region.hasCoord = ParseName(filepath.Base(name))
+	return region, nil
+}
+
+// NewWritable read the region of size bytes from rw, the chunks can be written
+func NewWritable(rw ReadWriterAt, size int64) (*File, error) {
+	var err error
+	var f *File
+
+	if f, err = New(rw, size); err != nil {
+		return nil, err
+	}
+	f.w = rw
+	return f, nil
+}
+
+// WriteChunk encode the tag and store it as the chunk at the local coordinates x, z.
+// The data are written in free sectors before the location of the chunk is updated,
+// so the previous chunk is kept if the writing fails.
+func (f *File) WriteChunk(x, z int, tag gonbt.Tag) error {
+	var err error
+	var data []byte
+
+	if data, err = gonbt.Marshal(tag, gonbt.CompressZLIB); err != nil {
+		return err
+	}
+	return f.WriteChunkData(x, z, CompressionZLIB, data)
+}
+
+// WriteChunkData store the data already compressed with the compression type as the
+// chunk at the local coordinates x, z. The error to remove the previous external file
+// of the chunk is returned even though the chunk is written
+func (f *File) WriteChunkData(x, z int, compression byte, data []byte) error {
+	var err error
+	var i int
+
+	if i, err = index(x, z); err != nil {
+		return err
+	}
+	if f.w == nil {
+		return ErrReadOnly
+	}
+	if err = f.initHeader(); err != nil {
+		return err
+	}
+	wasExternal := f.isExternal(i)
+
+	payload := make([]byte, chunkHeaderSize, chunkHeaderSize+len(data))
+	binary.BigEndian.PutUint32(payload, uint32(len(data)+1))
+	payload[4] = compression
+	payload = append(payload, data...)
+
+	tmpName := ""
+	if sectorCount(int64(len(payload))) > maxSectors {
+		// the data are stored in an external file and the region keep only the chunk header.
+		// The file is written under a temporary name, renamed once the location is updated
+		if !f.hasCoord {
+			return &ChunkError{X: x, Z: z, Err: ErrChunkTooLarge}
+		}
+		if tmpName, err = writeTempFile(f.externalPath(x, z), data); err != nil {
+			return err
+		}
+		// nothing to remove once the file is renamed
+		defer os.Remove(tmpName)
+		binary.BigEndian.PutUint32(payload, 1)
+		payload[4] = compression | externalFlag
+		payload = payload[:chunkHeaderSize]
+	}
+
+	count := sectorCount(int64(len(payload)))
+	offset := f.allocate(count)
+	padded := make([]byte, count*SectorSize)
+	copy(padded, payload)
+	if _, err = f.w.WriteAt(padded, offset*SectorSize); err != nil {
+		return err
+	}
+	if err = f.sync(); err != nil {
+		return err
+	}
+	if end := (offset + count) * SectorSize; end > f.size {
+		f.size = end
+	}
+
+	location, timestamp := f.locations[i], f.timestamps[i]
+	if err = f.writeEntry(i, uint32(offset)<<8|uint32(count), uint32(time.Now().Unix())); err != nil {
+		return err
+	}
+	if tmpName != "" {
+		if err = os.Rename(tmpName, f.externalPath(x, z)); err != nil {
+			// restore the previous location, which still reference the previous data
+			f.writeEntry(i, location, timestamp)
+			return err
+		}
+		return nil
+	}
+	if wasExternal {
+		return removeFile(f.externalPath(x, z))
+	}
+	return nil
+}
+
+// DeleteChunk remove the chunk at the local coordinates x, z, its sectors are free to the next chunks
+func (f *File) DeleteChunk(x, z int) error {
+	var err error
+	var i int
+
+	if i, err = index(x, z); err != nil {
+		return err
+	}
+	if f.w == nil {
+		return ErrReadOnly
+	}
+	if f.locations[i] == 0 {
+		return nil
+	}
+	wasExternal := f.isExternal(i)
+	if err = f.writeEntry(i, 0, 0); err != nil {
+		return err
+	}
+	if wasExternal {
+		return removeFile(f.externalPath(x, z))
+	}
+	return nil
+}
+
+// initHeader write the empty tables of a new region
+func (f *File) initHeader() error {
+	var err error
+
+	if f.size >= headerSize {
+		return nil
+	}
+	if _, err = f.w.WriteAt(make([]byte, headerSize), 0); err != nil {
+		return err
+	}
+	f.size = headerSize
+	return nil
+}
+
+// isExternal report if the chunk i is stored in an external file
+func (f *File) isExternal(i int) bool {
+	location := f.locations[i]
+	if location == 0 || !f.hasCoord {
+		return false
+	}
+	header := make([]byte, chunkHeaderSize)
+	if _, err := f.r.ReadAt(header, int64(location>>8)*SectorSize); err != nil {
+		return false
+	}
+	return header[4]&externalFlag != 0
+}
+
+// externalPath return the path of the external file of the chunk at the local coordinates x, z
+func (f *File) externalPath(x, z int) string {
+	name := "c." + strconv.Itoa(f.x*Width+x) + "." + strconv.Itoa(f.z*Width+z) + ".mcc"
+	return filepath.Join(f.dir, name)
+}
+
+// sectorCount return the number of sectors to store size bytes
+func sectorCount(size int64) int64 {
+	return (size + SectorSize - 1) / SectorSize
+}
+
+// allocate return the offset of the first free run of count sectors. The sectors of all
+// the stored chunks are used, including the chunk being replaced, to never overwrite
+// the data referenced by the location table.
+func (f *File) allocate(count int64) int64 {
+	total := sectorCount(f.size)
+	used := make([]bool, total)
+	used[0], used[1] = true, true
+	for _, location := range f.locations {
+		start, n := int64(location>>8), int64(location&0xff)
+		for s := start; s < start+n && s < total; s++ {
+			used[s] = true
+		}
+	}
+
+	run := int64(0)
+	for s := int64(2); s < total; s++ {
+		if used[s] {
+			run = 0
+			continue
+		}
+		run++
+		if run == count {
+			return s - count + 1
+		}
+	}
+	// extend the file, reusing the free sectors at its end
+	return total - run
+}
+
+// writeEntry update the location and the timestamp of the chunk i
+func (f *File) writeEntry(i int, location, timestamp uint32) error {
+	var err error
+
+	entry := make([]byte, 4)
+	binary.BigEndian.PutUint32(entry, location)
+	if _, err = f.w.WriteAt(entry, int64(i*4)); err != nil {
+		return err
+	}
+	f.locations[i] = location
+	binary.BigEndian.PutUint32(entry, timestamp)
+	if _, err = f.w.WriteAt(entry, int64(SectorSize+i*4)); err != nil {
+		return err
+	}
+	f.timestamps[i] = timestamp
+	return f.sync()
+}
+
+// sync flush the written data when the storage support it
+func (f *File) sync() error {
+	if s, ok := f.w.(syncer); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// writeTempFile write the data in a new temporary file beside name and return its path
+func writeTempFile(name string, data []byte) (string, error) {
+	var err error
+	var tmp *os.File
+
+	if tmp, err = ioutil.TempFile(filepath.Dir(name), filepath.Base(name)+".tmp"); err != nil {
+		return "", err
+	}
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// removeFile remove the external file of a chunk which is no longer referenced by
+// the region, the file already removed is not an error
+func removeFile(name string) error {
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
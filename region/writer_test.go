@@ -0,0 +1,268 @@
+package region
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymohl-cl/gonbt"
+)
+
+var errMockWrite = errors.New("expected_mock_error")
+
+// memFile is a region storage in memory, the writes fail after failAfter calls when it's set
+type memFile struct {
+	data      []byte
+	failAfter int
+}
+
+func (m *memFile) ReadAt(p []byte, off int64) (int, error) {
+	if off >= int64(len(m.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, m.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (m *memFile) WriteAt(p []byte, off int64) (int, error) {
+	if m.failAfter > 0 {
+		m.failAfter--
+		if m.failAfter == 0 {
+			return 0, errMockWrite
+		}
+	}
+	if end := int(off) + len(p); end > len(m.data) {
+		m.data = append(m.data, make([]byte, end-len(m.data))...)
+	}
+	return copy(m.data[off:], p), nil
+}
+
+// location return the sector offset and count of the chunk at x, z
+func (m *memFile) location(x, z int) (uint32, uint32) {
+	location := binary.BigEndian.Uint32(m.data[(x+z*Width)*4:])
+	return location >> 8, location & 0xff
+}
+
+// newTestChunkSize return a chunk tag which needs about size bytes once compressed
+func newTestChunkSize(size int) gonbt.Tag {
+	data := make([]byte, size)
+	rand.New(rand.NewSource(42)).Read(data)
+	chunk := &gonbt.CompoundT{}
+	chunk.Set("Data", &gonbt.ByteArrayT{Value: data})
+	return chunk
+}
+
+func TestFile_WriteChunk(t *testing.T) {
+	t.Run("should return an error because the region is read only", func(t *testing.T) {
+		f, err := New(bytes.NewReader([]byte{}), 0)
+		if assert.NoError(t, err) {
+			err = f.WriteChunk(0, 0, newTestChunkTag(0, 0))
+			assert.True(t, errors.Is(err, ErrReadOnly))
+		}
+	})
+	t.Run("should return an error because the coordinates are out of the region", func(t *testing.T) {
+		f, err := NewWritable(&memFile{}, 0)
+		if assert.NoError(t, err) {
+			err = f.WriteChunk(-1, 0, newTestChunkTag(0, 0))
+			assert.True(t, errors.Is(err, ErrCoordinates))
+		}
+	})
+	t.Run("should return an error because the chunk is too large without external file", func(t *testing.T) {
+		f, err := NewWritable(&memFile{}, 0)
+		if assert.NoError(t, err) {
+			err = f.WriteChunk(0, 0, newTestChunkSize(maxSectors*SectorSize))
+			assert.True(t, errors.Is(err, ErrChunkTooLarge))
+		}
+	})
+	t.Run("should be ok with a new region", func(t *testing.T) {
+		m := &memFile{}
+		tag := newTestChunkTag(1, 2)
+
+		f, err := NewWritable(m, 0)
+		if assert.NoError(t, err) {
+			err = f.WriteChunk(1, 2, tag)
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, 3*SectorSize, len(m.data))
+				offset, count := m.location(1, 2)
+				assert.EqualValues(t, 2, offset)
+				assert.EqualValues(t, 1, count)
+
+				reopened, err := New(m, int64(len(m.data)))
+				if assert.NoError(t, err) {
+					chunk, err := reopened.ReadChunk(1, 2)
+					if assert.NoError(t, err) {
+						assert.EqualValues(t, tag, chunk)
+					}
+					_, err = reopened.Timestamp(1, 2)
+					assert.NoError(t, err)
+				}
+			}
+		}
+	})
+	t.Run("should be ok with a relocated chunk and the free sectors reused", func(t *testing.T) {
+		m := &memFile{}
+		f, err := NewWritable(m, 0)
+		if !assert.NoError(t, err) {
+			return
+		}
+		large := newTestChunkSize(SectorSize + 100)
+
+		assert.NoError(t, f.WriteChunk(0, 0, newTestChunkTag(0, 0)))
+		assert.NoError(t, f.WriteChunk(1, 0, newTestChunkTag(1, 0)))
+		// the chunk 0, 0 grows to 2 sectors and moves after the chunk 1, 0
+		assert.NoError(t, f.WriteChunk(0, 0, large))
+		offset, count := m.location(0, 0)
+		assert.EqualValues(t, 4, offset)
+		assert.EqualValues(t, 2, count)
+		// the sector freed by the chunk 0, 0 is reused
+		assert.NoError(t, f.WriteChunk(2, 0, newTestChunkTag(2, 0)))
+		offset, _ = m.location(2, 0)
+		assert.EqualValues(t, 2, offset)
+		assert.EqualValues(t, 6*SectorSize, len(m.data))
+
+		chunk, err := f.ReadChunk(0, 0)
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, large, chunk)
+		}
+		chunk, err = f.ReadChunk(1, 0)
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, newTestChunkTag(1, 0), chunk)
+		}
+	})
+	t.Run("should keep the previous chunk because the writing failed", func(t *testing.T) {
+		m := &memFile{}
+		tag := newTestChunkTag(0, 0)
+		f, err := NewWritable(m, 0)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.NoError(t, f.WriteChunk(0, 0, tag))
+
+		for _, failAfter := range []int{1, 2} {
+			// fail on the data writing, then on the location writing
+			m.failAfter = failAfter
+			err = f.WriteChunk(0, 0, newTestChunkTag(9, 9))
+			if assert.Error(t, err) {
+				assert.EqualValues(t, errMockWrite, err)
+			}
+			reopened, err := New(m, int64(len(m.data)))
+			if assert.NoError(t, err) {
+				chunk, err := reopened.ReadChunk(0, 0)
+				if assert.NoError(t, err) {
+					assert.EqualValues(t, tag, chunk)
+				}
+			}
+		}
+	})
+}
+
+func TestFile_DeleteChunk(t *testing.T) {
+	t.Run("should return an error because the region is read only", func(t *testing.T) {
+		f, err := New(bytes.NewReader([]byte{}), 0)
+		if assert.NoError(t, err) {
+			assert.True(t, errors.Is(f.DeleteChunk(0, 0), ErrReadOnly))
+		}
+	})
+	t.Run("should be ok", func(t *testing.T) {
+		m := &memFile{}
+		f, err := NewWritable(m, 0)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.NoError(t, f.WriteChunk(0, 0, newTestChunkTag(0, 0)))
+		assert.NoError(t, f.DeleteChunk(0, 0))
+		assert.NoError(t, f.DeleteChunk(0, 1))
+		assert.False(t, f.HasChunk(0, 0))
+		_, err = f.ReadChunk(0, 0)
+		assert.True(t, errors.Is(err, ErrChunkNotFound))
+		// the sector of the deleted chunk is reused
+		assert.NoError(t, f.WriteChunk(5, 5, newTestChunkTag(5, 5)))
+		offset, _ := m.location(5, 5)
+		assert.EqualValues(t, 2, offset)
+	})
+}
+
+func TestOpenFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "region")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "r.0.1.mca")
+
+	t.Run("should be ok with a new file", func(t *testing.T) {
+		tag := newTestChunkTag(0, 32)
+
+		f, err := OpenFile(name, os.O_RDWR|os.O_CREATE, 0644)
+		if assert.NoError(t, err) {
+			assert.NoError(t, f.WriteChunk(0, 0, tag))
+			assert.NoError(t, f.Close())
+		}
+		f, err = Open(name)
+		if assert.NoError(t, err) {
+			defer f.Close()
+			chunk, err := f.ReadChunk(0, 0)
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, tag, chunk)
+			}
+		}
+	})
+	t.Run("should be ok with a chunk stored in an external file", func(t *testing.T) {
+		large := newTestChunkSize(maxSectors * SectorSize)
+		external := filepath.Join(dir, "c.2.35.mcc")
+
+		f, err := OpenFile(name, os.O_RDWR, 0)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer f.Close()
+		if assert.NoError(t, f.WriteChunk(2, 3, large)) {
+			assert.FileExists(t, external)
+			chunk, err := f.ReadChunk(2, 3)
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, large, chunk)
+			}
+		}
+		// the external file is removed once the chunk fits in the region
+		if assert.NoError(t, f.WriteChunk(2, 3, newTestChunkTag(2, 35))) {
+			assert.NoFileExists(t, external)
+		}
+	})
+	t.Run("should keep the previous external chunk because the writing failed", func(t *testing.T) {
+		large := newTestChunkSize(maxSectors * SectorSize)
+		m := &memFile{}
+		f, err := NewWritable(m, 0)
+		if !assert.NoError(t, err) {
+			return
+		}
+		f.dir, f.hasCoord = dir, true
+		assert.NoError(t, f.WriteChunk(4, 4, large))
+
+		for _, failAfter := range []int{1, 2} {
+			// fail on the data writing, then on the location writing
+			m.failAfter = failAfter
+			err = f.WriteChunk(4, 4, newTestChunkSize(maxSectors*SectorSize+1))
+			if assert.Error(t, err) {
+				assert.EqualValues(t, errMockWrite, err)
+			}
+			chunk, err := f.ReadChunk(4, 4)
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, large, chunk)
+			}
+			// the temporary file is removed
+			matches, _ := filepath.Glob(filepath.Join(dir, "*.tmp*"))
+			assert.Empty(t, matches)
+		}
+	})
+}
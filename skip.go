@@ -0,0 +1,148 @@
+package gonbt
+
+import (
+	"io"
+	"io/ioutil"
+)
+
+// skipper is implemented by the readers with the fixed-size encoding, which
+// can skip the data without decoding it
+type skipper interface {
+	skip(n int64) error
+}
+
+// skip n bytes of the flux
+func (r *reader) skip(n int64) error {
+	var err error
+
+	if _, err = io.CopyN(ioutil.Discard, r.flux, n); err != nil {
+		return truncated(err)
+	}
+	return nil
+}
+
+// skip n bytes of data
+func (r *bytesReader) skip(n int64) error {
+	var err error
+
+	if _, err = r.next(n); err != nil {
+		return err
+	}
+	return nil
+}
+
+// payloadSizes of the tags with a fixed size
+var payloadSizes = map[byte]int64{
+	TagByte:   1,
+	TagShort:  2,
+	TagInt:    4,
+	TagLong:   8,
+	TagFloat:  4,
+	TagDouble: 8,
+}
+
+// arrayElemSizes of the array tags
+var arrayElemSizes = map[byte]int64{
+	TagByteArray: 1,
+	TagIntArray:  4,
+	TagLongArray: 8,
+}
+
+// skipTag skip the payload of a tag of type tagT, the readers which can't skip
+// decode the payload. The errors are not located in the skipped payload
+func skipTag(reader Reader, tagT byte) error {
+	var err error
+
+	s, ok := reader.(skipper)
+	if !ok {
+		var t Tag
+		if t, err = NewTag(tagT, ""); err != nil {
+			return err
+		}
+		return t.Read(reader)
+	}
+	if size, ok := payloadSizes[tagT]; ok {
+		return s.skip(size)
+	}
+	if size, ok := arrayElemSizes[tagT]; ok {
+		var n int32
+		if n, err = reader.Int(); err != nil {
+			return err
+		}
+		if err = checkLength(reader, n); err != nil {
+			return err
+		}
+		return s.skip(int64(n) * size)
+	}
+	switch tagT {
+	case TagString:
+		return skipString(reader, s)
+	case TagList:
+		return skipList(reader, s)
+	case TagCompound:
+		return skipCompound(reader, s)
+	default:
+		return ErrUnknownTagType
+	}
+}
+
+// skipString skip a string payload
+func skipString(reader Reader, s skipper) error {
+	var size int16
+	var err error
+
+	if size, err = reader.Short(); err != nil {
+		return err
+	}
+	return s.skip(int64(uint16(size)))
+}
+
+// skipList skip a list payload
+func skipList(reader Reader, s skipper) error {
+	var err error
+	var tagT byte
+	var n int32
+
+	if err = enter(reader); err != nil {
+		return err
+	}
+	defer leave(reader)
+	if tagT, err = reader.Byte(); err != nil {
+		return err
+	}
+	if n, err = reader.Int(); err != nil {
+		return err
+	}
+	if err = checkLength(reader, n); err != nil {
+		return err
+	}
+	if size, ok := payloadSizes[tagT]; ok {
+		return s.skip(int64(n) * size)
+	}
+	for i := int32(0); i < n; i++ {
+		if err = skipTag(reader, tagT); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipCompound skip a compound payload, the names of the elements are skipped too
+func skipCompound(reader Reader, s skipper) error {
+	var err error
+	var tagT byte
+
+	if err = enter(reader); err != nil {
+		return err
+	}
+	defer leave(reader)
+	for tagT, err = reader.Byte(); tagT != TagEnd && err == nil; tagT, err = reader.Byte() {
+		if err = skipString(reader, s); err != nil {
+			return err
+		}
+		if err = skipTag(reader, tagT); err != nil {
+			return err
+		}
+	}
+	return truncated(err)
+}
@@ -0,0 +1,619 @@
+package gonbt
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// read and write the stringified nbt (SNBT) used by the minecraft commands, like:
+// {Count:1b,id:"minecraft:stone",tag:{Damage:0s}}
+// https://minecraft.fandom.com/wiki/NBT_format#SNBT_format
+
+// snbtMaxDepth is the maximum nesting of lists and compounds like minecraft
+const snbtMaxDepth = 512
+
+// patterns of the unquoted values, a token which doesn't match any of them is a string
+var (
+	snbtByte         = regexp.MustCompile(`^[-+]?(?:0|[1-9][0-9]*)[bB]$`)
+	snbtShort        = regexp.MustCompile(`^[-+]?(?:0|[1-9][0-9]*)[sS]$`)
+	snbtInt          = regexp.MustCompile(`^[-+]?(?:0|[1-9][0-9]*)$`)
+	snbtLong         = regexp.MustCompile(`^[-+]?(?:0|[1-9][0-9]*)[lL]$`)
+	snbtFloat        = regexp.MustCompile(`^[-+]?(?:[0-9]+[.]?|[0-9]*[.][0-9]+)(?:[eE][-+]?[0-9]+)?[fF]$`)
+	snbtDouble       = regexp.MustCompile(`^[-+]?(?:[0-9]+[.]?|[0-9]*[.][0-9]+)(?:[eE][-+]?[0-9]+)?[dD]$`)
+	snbtDoubleNoSufx = regexp.MustCompile(`^[-+]?(?:[0-9]+[.]|[0-9]*[.][0-9]+)(?:[eE][-+]?[0-9]+)?$`)
+)
+
+// ParseSNBT return the tag described by the stringified nbt s
+func ParseSNBT(s string) (Tag, error) {
+	var err error
+	var t Tag
+
+	p := &snbtParser{data: s}
+	if t, err = p.value("", 0); err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.data) {
+		return nil, p.error(errorSNBTTrailing)
+	}
+	return t, nil
+}
+
+// snbtParser read the snbt data from the position pos
+type snbtParser struct {
+	data string
+	pos  int
+}
+
+// error return a SyntaxError at the current position
+func (p *snbtParser) error(msg string) error {
+	return p.errorAt(p.pos, msg)
+}
+
+func (p *snbtParser) errorAt(pos int, msg string) error {
+	line := 1 + strings.Count(p.data[:pos], "\n")
+	lineStart := strings.LastIndex(p.data[:pos], "\n") + 1
+	column := 1 + utf8.RuneCountInString(p.data[lineStart:pos])
+	return &SyntaxError{Offset: pos, Line: line, Column: column, Msg: msg}
+}
+
+func (p *snbtParser) skipSpaces() {
+	for p.pos < len(p.data) {
+		switch p.data[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// peek return the next character or 0 at the end of data
+func (p *snbtParser) peek() byte {
+	if p.pos >= len(p.data) {
+		return 0
+	}
+	return p.data[p.pos]
+}
+
+// expect skip the spaces and the character c
+func (p *snbtParser) expect(c byte) error {
+	p.skipSpaces()
+	if p.peek() != c {
+		return p.error("expected '" + string(c) + "'")
+	}
+	p.pos++
+	return nil
+}
+
+// isUnquoted report if c is allowed in an unquoted string
+func isUnquoted(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' ||
+		c == '_' || c == '-' || c == '.' || c == '+'
+}
+
+// value parse the next tag with the name
+func (p *snbtParser) value(name string, depth int) (Tag, error) {
+	p.skipSpaces()
+	switch p.peek() {
+	case 0:
+		return nil, p.error(errorSNBTValue)
+	case '{':
+		if depth >= snbtMaxDepth {
+			return nil, p.error(errorSNBTDepth)
+		}
+		return p.compound(name, depth+1)
+	case '[':
+		if depth >= snbtMaxDepth {
+			return nil, p.error(errorSNBTDepth)
+		}
+		if p.pos+2 < len(p.data) && p.data[p.pos+2] == ';' {
+			return p.array(name)
+		}
+		return p.list(name, depth+1)
+	case '"', '\'':
+		var err error
+		var str string
+
+		if str, err = p.quoted(); err != nil {
+			return nil, err
+		}
+		return &StringT{Name: name, Value: str}, nil
+	default:
+		start := p.pos
+		token := p.unquoted()
+		if token == "" {
+			return nil, p.errorAt(start, errorSNBTValue)
+		}
+		return primitive(name, token), nil
+	}
+}
+
+// unquoted read an unquoted string
+func (p *snbtParser) unquoted() string {
+	start := p.pos
+	for p.pos < len(p.data) && isUnquoted(p.data[p.pos]) {
+		p.pos++
+	}
+	return p.data[start:p.pos]
+}
+
+// quoted read a string between single or double quotes
+func (p *snbtParser) quoted() (string, error) {
+	var b strings.Builder
+
+	quote := p.data[p.pos]
+	p.pos++
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		case c == '\\':
+			if p.pos+1 >= len(p.data) {
+				p.pos++
+				return "", p.error(errorSNBTString)
+			}
+			p.pos++
+			switch e := p.data[p.pos]; e {
+			case '\\', '"', '\'':
+				b.WriteByte(e)
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'u':
+				if p.pos+5 > len(p.data) {
+					return "", p.error(errorSNBTEscape)
+				}
+				r, err := strconv.ParseUint(p.data[p.pos+1:p.pos+5], 16, 16)
+				if err != nil {
+					return "", p.error(errorSNBTEscape)
+				}
+				b.WriteRune(rune(r))
+				p.pos += 4
+			default:
+				return "", p.error(errorSNBTEscape)
+			}
+			p.pos++
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", p.error(errorSNBTString)
+}
+
+// key read the name of a compound element
+func (p *snbtParser) key() (string, error) {
+	p.skipSpaces()
+	if c := p.peek(); c == '"' || c == '\'' {
+		return p.quoted()
+	}
+	key := p.unquoted()
+	if key == "" {
+		return "", p.error(errorSNBTKey)
+	}
+	return key, nil
+}
+
+// compound parse {<name>:<value>,<name>:<value>,...}
+func (p *snbtParser) compound(name string, depth int) (Tag, error) {
+	var err error
+
+	t := &CompoundT{Name: name, Value: make(map[string]interface{})}
+	p.pos++
+	p.skipSpaces()
+	if p.peek() == '}' {
+		p.pos++
+		return t, nil
+	}
+	for {
+		var key string
+		var elem Tag
+
+		if key, err = p.key(); err != nil {
+			return nil, err
+		}
+		if err = p.expect(':'); err != nil {
+			return nil, err
+		}
+		if elem, err = p.value(key, depth); err != nil {
+			return nil, err
+		}
+		t.Set(key, elem)
+
+		p.skipSpaces()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return t, nil
+		default:
+			return nil, p.error(errorSNBTCompoundEnd)
+		}
+	}
+}
+
+// list parse [<value>,<value>,...]
+func (p *snbtParser) list(name string, depth int) (Tag, error) {
+	var err error
+	var listT byte
+
+	t := &ListT{Name: name, Value: []interface{}{}}
+	p.pos++
+	p.skipSpaces()
+	if p.peek() == ']' {
+		p.pos++
+		return t, nil
+	}
+	for {
+		var elem Tag
+		var tagT byte
+
+		p.skipSpaces()
+		start := p.pos
+		if elem, err = p.value("", depth); err != nil {
+			return nil, err
+		}
+		tagT, _ = TagType(elem)
+		if len(t.Value) == 0 {
+			listT = tagT
+			t.ElemType = tagT
+		} else if tagT != listT {
+			return nil, p.errorAt(start, "can't insert "+tagName(tagT)+" into a list of "+tagName(listT))
+		}
+		t.Value = append(t.Value, elem)
+
+		p.skipSpaces()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return t, nil
+		default:
+			return nil, p.error(errorSNBTListEnd)
+		}
+	}
+}
+
+// array parse [B;<byte>,...], [I;<int>,...] or [L;<long>,...]
+func (p *snbtParser) array(name string) (Tag, error) {
+	var t Tag
+	var elemT byte
+
+	switch p.data[p.pos+1] {
+	case 'B':
+		t, elemT = &ByteArrayT{Name: name, Value: []byte{}}, TagByte
+	case 'I':
+		t, elemT = &IntArrayT{Name: name, Value: []int32{}}, TagInt
+	case 'L':
+		t, elemT = &LongArrayT{Name: name, Value: []int64{}}, TagLong
+	default:
+		return nil, p.errorAt(p.pos+1, errorSNBTArrayType)
+	}
+	p.pos += 3
+	p.skipSpaces()
+	if p.peek() == ']' {
+		p.pos++
+		return t, nil
+	}
+	for {
+		var tagT byte
+
+		p.skipSpaces()
+		start := p.pos
+		token := p.unquoted()
+		if token == "" {
+			return nil, p.error(errorSNBTValue)
+		}
+		elem := primitive("", token)
+		if tagT, _ = TagType(elem); tagT != elemT {
+			arrayT, _ := TagType(t)
+			return nil, p.errorAt(start, "can't insert "+tagName(tagT)+" into "+tagName(arrayT))
+		}
+		switch array := t.(type) {
+		case *ByteArrayT:
+			array.Value = append(array.Value, elem.(*ByteT).Value)
+		case *IntArrayT:
+			array.Value = append(array.Value, elem.(*IntT).Value)
+		case *LongArrayT:
+			array.Value = append(array.Value, elem.(*LongT).Value)
+		}
+
+		p.skipSpaces()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return t, nil
+		default:
+			return nil, p.error(errorSNBTListEnd)
+		}
+	}
+}
+
+// primitive return the tag of an unquoted token, the numbers out of range are strings like minecraft
+func primitive(name, token string) Tag {
+	switch {
+	case snbtByte.MatchString(token):
+		if v, err := strconv.ParseInt(token[:len(token)-1], 10, 8); err == nil {
+			return &ByteT{Name: name, Value: byte(v)}
+		}
+	case snbtShort.MatchString(token):
+		if v, err := strconv.ParseInt(token[:len(token)-1], 10, 16); err == nil {
+			return &ShortT{Name: name, Value: int16(v)}
+		}
+	case snbtInt.MatchString(token):
+		if v, err := strconv.ParseInt(token, 10, 32); err == nil {
+			return &IntT{Name: name, Value: int32(v)}
+		}
+	case snbtLong.MatchString(token):
+		if v, err := strconv.ParseInt(token[:len(token)-1], 10, 64); err == nil {
+			return &LongT{Name: name, Value: v}
+		}
+	case snbtFloat.MatchString(token):
+		if v, err := strconv.ParseFloat(token[:len(token)-1], 32); err == nil {
+			return &FloatT{Name: name, Value: float32(v)}
+		}
+	case snbtDouble.MatchString(token):
+		if v, err := strconv.ParseFloat(token[:len(token)-1], 64); err == nil {
+			return &DoubleT{Name: name, Value: v}
+		}
+	case snbtDoubleNoSufx.MatchString(token):
+		if v, err := strconv.ParseFloat(token, 64); err == nil {
+			return &DoubleT{Name: name, Value: v}
+		}
+	case strings.EqualFold(token, "true"):
+		return &ByteT{Name: name, Value: 1}
+	case strings.EqualFold(token, "false"):
+		return &ByteT{Name: name, Value: 0}
+	}
+	return &StringT{Name: name, Value: token}
+}
+
+// SNBTOptions to format the stringified nbt
+type SNBTOptions struct {
+	// Indent is written once by nesting level on each line, the output is
+	// compact on a single line like minecraft when Indent is empty
+	Indent string
+	// SortKeys write the elements of the compounds sorted by name rather than
+	// in the order of the compounds
+	SortKeys bool
+}
+
+// MarshalSNBT return the stringified nbt of the tag
+func MarshalSNBT(t Tag, opts SNBTOptions) (string, error) {
+	var err error
+	var b strings.Builder
+
+	w := &snbtWriter{b: &b, opts: opts}
+	if err = w.value(t, 0); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// snbtWriter write the snbt in the builder b
+type snbtWriter struct {
+	b    *strings.Builder
+	opts SNBTOptions
+}
+
+// newline start a new line indented at the depth in pretty mode
+func (w *snbtWriter) newline(depth int) {
+	if w.opts.Indent == "" {
+		return
+	}
+	w.b.WriteByte('\n')
+	for i := 0; i < depth; i++ {
+		w.b.WriteString(w.opts.Indent)
+	}
+}
+
+// separator write the separator between two elements, inline write it on the same line
+func (w *snbtWriter) separator(depth int, inline bool) {
+	w.b.WriteByte(',')
+	if w.opts.Indent == "" {
+		return
+	}
+	if inline {
+		w.b.WriteByte(' ')
+		return
+	}
+	w.newline(depth)
+}
+
+func (w *snbtWriter) value(t Tag, depth int) error {
+	switch tag := t.(type) {
+	case *ByteT:
+		w.b.WriteString(strconv.Itoa(int(int8(tag.Value))) + "b")
+	case *ShortT:
+		w.b.WriteString(strconv.Itoa(int(tag.Value)) + "s")
+	case *IntT:
+		w.b.WriteString(strconv.Itoa(int(tag.Value)))
+	case *LongT:
+		w.b.WriteString(strconv.FormatInt(tag.Value, 10) + "L")
+	case *FloatT:
+		return w.float(float64(tag.Value), 32, "f")
+	case *DoubleT:
+		return w.float(tag.Value, 64, "d")
+	case *StringT:
+		w.b.WriteString(quoteSNBT(tag.Value, false))
+	case *ByteArrayT:
+		w.b.WriteString("[B;")
+		for i, v := range tag.Value {
+			w.arraySeparator(i)
+			w.b.WriteString(strconv.Itoa(int(int8(v))) + "b")
+		}
+		w.b.WriteByte(']')
+	case *IntArrayT:
+		w.b.WriteString("[I;")
+		for i, v := range tag.Value {
+			w.arraySeparator(i)
+			w.b.WriteString(strconv.Itoa(int(v)))
+		}
+		w.b.WriteByte(']')
+	case *LongArrayT:
+		w.b.WriteString("[L;")
+		for i, v := range tag.Value {
+			w.arraySeparator(i)
+			w.b.WriteString(strconv.FormatInt(v, 10) + "L")
+		}
+		w.b.WriteByte(']')
+	case *ListT:
+		return w.list(tag, depth)
+	case *CompoundT:
+		return w.compound(tag, depth)
+	default:
+		return ErrUnknownTagType
+	}
+	return nil
+}
+
+// arraySeparator write the separator before the element i of an array
+func (w *snbtWriter) arraySeparator(i int) {
+	if i > 0 {
+		w.b.WriteByte(',')
+	}
+	if w.opts.Indent != "" {
+		w.b.WriteByte(' ')
+	}
+}
+
+// float write v with the suffix, the integral values keep a decimal part like minecraft
+func (w *snbtWriter) float(v float64, bitSize int, suffix string) error {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return errors.New(errorSNBTFloat)
+	}
+	str := strconv.FormatFloat(v, 'g', -1, bitSize)
+	if !strings.ContainsAny(str, ".e") {
+		str += ".0"
+	}
+	w.b.WriteString(str + suffix)
+	return nil
+}
+
+func (w *snbtWriter) list(t *ListT, depth int) error {
+	var err error
+
+	if len(t.Value) == 0 {
+		w.b.WriteString("[]")
+		return nil
+	}
+	// the lists of primitives are kept on a single line in pretty mode
+	inline := true
+	for _, elem := range t.Value {
+		switch elem.(type) {
+		case *ListT, *CompoundT:
+			inline = false
+		}
+	}
+
+	w.b.WriteByte('[')
+	if !inline {
+		w.newline(depth + 1)
+	}
+	for i, elem := range t.Value {
+		if _, ok := elem.(Tag); !ok {
+			return ErrUnknownTagType
+		}
+		if i > 0 {
+			w.separator(depth+1, inline)
+		}
+		if err = w.value(elem.(Tag), depth+1); err != nil {
+			return err
+		}
+	}
+	if !inline {
+		w.newline(depth)
+	}
+	w.b.WriteByte(']')
+	return nil
+}
+
+func (w *snbtWriter) compound(t *CompoundT, depth int) error {
+	var err error
+
+	if len(t.Value) == 0 {
+		w.b.WriteString("{}")
+		return nil
+	}
+	keys := t.Keys()
+	if w.opts.SortKeys {
+		sort.Strings(keys)
+	}
+
+	w.b.WriteByte('{')
+	w.newline(depth + 1)
+	for i, key := range keys {
+		value := t.Value[key]
+		if _, ok := value.(Tag); !ok {
+			return ErrUnknownTagType
+		}
+		if i > 0 {
+			w.separator(depth+1, false)
+		}
+		w.b.WriteString(quoteSNBT(key, true))
+		w.b.WriteByte(':')
+		if w.opts.Indent != "" {
+			w.b.WriteByte(' ')
+		}
+		if err = w.value(value.(Tag), depth+1); err != nil {
+			return err
+		}
+	}
+	w.newline(depth)
+	w.b.WriteByte('}')
+	return nil
+}
+
+// quoteSNBT return s quoted when it's needed. A value which would be read
+// as another type than a string, like 1b or true, is always quoted.
+func quoteSNBT(s string, key bool) string {
+	needQuotes := s == ""
+	for i := 0; i < len(s) && !needQuotes; i++ {
+		needQuotes = !isUnquoted(s[i])
+	}
+	if !needQuotes && !key {
+		_, isString := primitive("", s).(*StringT)
+		needQuotes = !isString
+	}
+	if !needQuotes {
+		return s
+	}
+	return quoteString(s)
+}
+
+// quoteString return s between quotes, the double quotes are used unless s contains some
+func quoteString(s string) string {
+	quote := byte('"')
+	if strings.IndexByte(s, '"') >= 0 && strings.IndexByte(s, '\'') < 0 {
+		quote = '\''
+	}
+	var b strings.Builder
+	b.WriteByte(quote)
+	for i := 0; i < len(s); i++ {
+		if s[i] == quote || s[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteByte(quote)
+	return b.String()
+}
@@ -0,0 +1,213 @@
+package gonbt
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSNBT(t *testing.T) {
+	t.Run("should return an error because the data is empty", func(t *testing.T) {
+		tag, err := ParseSNBT("")
+		if assert.Error(t, err) {
+			assert.EqualValues(t, "line 1, column 1: "+errorSNBTValue, err.Error())
+			assert.Nil(t, tag)
+		}
+	})
+	t.Run("should return an error with the line and the column", func(t *testing.T) {
+		tag, err := ParseSNBT("{\n  Count: 1b\n  id: \"minecraft:stone\"\n}")
+		var syntaxErr *SyntaxError
+		if assert.True(t, errors.As(err, &syntaxErr)) {
+			assert.EqualValues(t, 3, syntaxErr.Line)
+			assert.EqualValues(t, 3, syntaxErr.Column)
+			assert.EqualValues(t, errorSNBTCompoundEnd, syntaxErr.Msg)
+			assert.Nil(t, tag)
+		}
+	})
+	t.Run("should return an error because the string is not terminated", func(t *testing.T) {
+		_, err := ParseSNBT(`{id:"minecraft:stone}`)
+		if assert.Error(t, err) {
+			assert.EqualValues(t, "line 1, column 22: "+errorSNBTString, err.Error())
+		}
+	})
+	t.Run("should return an error because the escape sequence is invalid", func(t *testing.T) {
+		_, err := ParseSNBT(`"\q"`)
+		if assert.Error(t, err) {
+			assert.EqualValues(t, "line 1, column 3: "+errorSNBTEscape, err.Error())
+		}
+	})
+	t.Run("should return an error because the list is mixed", func(t *testing.T) {
+		_, err := ParseSNBT(`[1, 2b]`)
+		if assert.Error(t, err) {
+			assert.EqualValues(t, "line 1, column 5: can't insert TAG_Byte into a list of TAG_Int", err.Error())
+		}
+	})
+	t.Run("should return an error because the array element has the wrong type", func(t *testing.T) {
+		_, err := ParseSNBT(`[L; 1L, 2]`)
+		if assert.Error(t, err) {
+			assert.EqualValues(t, "line 1, column 9: can't insert TAG_Int into TAG_Long_Array", err.Error())
+		}
+	})
+	t.Run("should return an error because the array type is unknown", func(t *testing.T) {
+		_, err := ParseSNBT(`[S; 1s]`)
+		if assert.Error(t, err) {
+			assert.EqualValues(t, "line 1, column 2: "+errorSNBTArrayType, err.Error())
+		}
+	})
+	t.Run("should return an error because of trailing data", func(t *testing.T) {
+		_, err := ParseSNBT(`{} {}`)
+		if assert.Error(t, err) {
+			assert.EqualValues(t, "line 1, column 4: "+errorSNBTTrailing, err.Error())
+		}
+	})
+	t.Run("should be ok with all the primitive types", func(t *testing.T) {
+		expectedTag := &ListT{ElemType: TagCompound, Value: []interface{}{
+			&CompoundT{Value: map[string]interface{}{
+				"byte":            &ByteT{Name: "byte", Value: 0xff},
+				"short":           &ShortT{Name: "short", Value: 300},
+				"int":             &IntT{Name: "int", Value: -7},
+				"long":            &LongT{Name: "long", Value: 1 << 40},
+				"float":           &FloatT{Name: "float", Value: 1.5},
+				"double":          &DoubleT{Name: "double", Value: 0.25},
+				"decimal":         &DoubleT{Name: "decimal", Value: 3},
+				"bool":            &ByteT{Name: "bool", Value: 1},
+				"unquoted":        &StringT{Name: "unquoted", Value: "minecraft.stone"},
+				"overflow":        &StringT{Name: "overflow", Value: "300b"},
+				"quoted":          &StringT{Name: "quoted", Value: "it's \"quoted\"\n"},
+				"single":          &StringT{Name: "single", Value: `say "hi"`},
+				"key with spaces": &StringT{Name: "key with spaces", Value: "é"},
+			}, Order: []string{"byte", "short", "int", "long", "float", "double", "decimal", "bool",
+				"unquoted", "overflow", "quoted", "single", "key with spaces"}},
+		}}
+
+		tag, err := ParseSNBT(`[{byte:-1b, short:300s, int:-7, long:1099511627776L, float:1.5f, double:.25d,
+			decimal:3., bool:true, unquoted:minecraft.stone, overflow:300b, quoted:"it's \"quoted\"\n",
+			single:'say "hi"', "key with spaces":"é"}]`)
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, expectedTag, tag)
+		}
+	})
+	t.Run("should be ok with the arrays and the nested tags", func(t *testing.T) {
+		expectedTag := &CompoundT{Value: map[string]interface{}{
+			"Count": &ByteT{Name: "Count", Value: 1},
+			"id":    &StringT{Name: "id", Value: "minecraft:stone"},
+			"tag": &CompoundT{Name: "tag", Value: map[string]interface{}{
+				"Damage": &ShortT{Name: "Damage", Value: 0},
+				"Bytes":  &ByteArrayT{Name: "Bytes", Value: []byte{1, 0xfe}},
+				"Ints":   &IntArrayT{Name: "Ints", Value: []int32{}},
+				"Longs":  &LongArrayT{Name: "Longs", Value: []int64{3}},
+				"Lists":  &ListT{Name: "Lists", ElemType: TagList, Value: []interface{}{&ListT{Value: []interface{}{}}, &ListT{ElemType: TagInt, Value: []interface{}{&IntT{Value: 1}}}}},
+			}, Order: []string{"Damage", "Bytes", "Ints", "Longs", "Lists"}},
+		}, Order: []string{"Count", "id", "tag"}}
+
+		tag, err := ParseSNBT(`{Count:1b,id:"minecraft:stone",tag:{Damage:0s,Bytes:[B;1b,-2b],Ints:[I;],Longs:[L; 3L],Lists:[[],[1]]}}`)
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, expectedTag, tag)
+		}
+	})
+}
+
+func TestMarshalSNBT(t *testing.T) {
+	item := &CompoundT{Value: map[string]interface{}{
+		"Count": &ByteT{Name: "Count", Value: 1},
+		"id":    &StringT{Name: "id", Value: "minecraft:stone"},
+		"tag": &CompoundT{Name: "tag", Value: map[string]interface{}{
+			"Damage": &ShortT{Name: "Damage", Value: 0},
+			"Pos":    &ListT{Name: "Pos", Value: []interface{}{&DoubleT{Value: 1}, &DoubleT{Value: -0.5}}},
+			"Items":  &ListT{Name: "Items", Value: []interface{}{&CompoundT{Value: map[string]interface{}{"Slot": &ByteT{Name: "Slot", Value: 0xff}}}}},
+			"Colors": &IntArrayT{Name: "Colors", Value: []int32{1, -2}},
+		}},
+	}}
+
+	t.Run("should return an error because the tag is not supported", func(t *testing.T) {
+		str, err := MarshalSNBT(&ListT{Value: []interface{}{&fakeTag{}}}, SNBTOptions{})
+		if assert.Error(t, err) {
+			assert.EqualValues(t, errorTag, err.Error())
+			assert.Empty(t, str)
+		}
+	})
+	t.Run("should return an error because the float is NaN", func(t *testing.T) {
+		_, err := MarshalSNBT(&FloatT{Value: float32(math.NaN())}, SNBTOptions{})
+		if assert.Error(t, err) {
+			assert.EqualValues(t, errorSNBTFloat, err.Error())
+		}
+	})
+	t.Run("should be ok with the compact mode", func(t *testing.T) {
+		expectedSNBT := `{Count:1b,id:"minecraft:stone",tag:{Colors:[I;1,-2],Damage:0s,Items:[{Slot:-1b}],Pos:[1.0d,-0.5d]}}`
+
+		str, err := MarshalSNBT(item, SNBTOptions{SortKeys: true})
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, expectedSNBT, str)
+		}
+	})
+	t.Run("should be ok with the order of the compound", func(t *testing.T) {
+		tag := &CompoundT{Value: map[string]interface{}{
+			"b": &IntT{Name: "b", Value: 1},
+			"a": &IntT{Name: "a", Value: 2},
+			"c": &IntT{Name: "c", Value: 3},
+		}, Order: []string{"c", "b"}}
+		expectedSNBT := `{c:3,b:1,a:2}`
+
+		str, err := MarshalSNBT(tag, SNBTOptions{})
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, expectedSNBT, str)
+		}
+	})
+	t.Run("should be ok with the pretty mode", func(t *testing.T) {
+		expectedSNBT := "{\n" +
+			"\tCount: 1b,\n" +
+			"\tid: \"minecraft:stone\",\n" +
+			"\ttag: {\n" +
+			"\t\tColors: [I; 1, -2],\n" +
+			"\t\tDamage: 0s,\n" +
+			"\t\tItems: [\n" +
+			"\t\t\t{\n" +
+			"\t\t\t\tSlot: -1b\n" +
+			"\t\t\t}\n" +
+			"\t\t],\n" +
+			"\t\tPos: [1.0d, -0.5d]\n" +
+			"\t}\n" +
+			"}"
+
+		str, err := MarshalSNBT(item, SNBTOptions{Indent: "\t", SortKeys: true})
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, expectedSNBT, str)
+		}
+	})
+	t.Run("should be ok with the strings quoted only when it's needed", func(t *testing.T) {
+		tag := &ListT{Value: []interface{}{
+			&StringT{Value: "stone"},
+			&StringT{Value: "1b"},
+			&StringT{Value: "true"},
+			&StringT{Value: ""},
+			&StringT{Value: `say "hi"`},
+			&StringT{Value: `it's "quoted" \o/`},
+		}}
+		expectedSNBT := `[stone,"1b","true","",'say "hi"',"it's \"quoted\" \\o/"]`
+
+		str, err := MarshalSNBT(tag, SNBTOptions{})
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, expectedSNBT, str)
+		}
+	})
+	t.Run("should be ok with a round trip", func(t *testing.T) {
+		tag := &CompoundT{Value: map[string]interface{}{
+			"a key": &LongArrayT{Name: "a key", Value: []int64{1 << 40}},
+			"bytes": &ByteArrayT{Name: "bytes", Value: []byte{0, 0x80}},
+			"float": &FloatT{Name: "float", Value: 1e20},
+			"long":  &LongT{Name: "long", Value: -3},
+			"empty": &ListT{Name: "empty", Value: []interface{}{}},
+			"int":   &IntT{Name: "int", Value: 42},
+		}, Order: []string{"long", "a key", "bytes", "float", "empty", "int"}}
+
+		str, err := MarshalSNBT(tag, SNBTOptions{Indent: "  "})
+		if assert.NoError(t, err) {
+			parsed, err := ParseSNBT(str)
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, tag, parsed)
+			}
+		}
+	})
+}
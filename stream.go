@@ -0,0 +1,369 @@
+package gonbt
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Decoder read the nbt tags from an input stream, the gzip and zlib compressions are
+// detected on the first Decode. The decoder may read data beyond the requested tags.
+type Decoder struct {
+	r        *bufio.Reader
+	compress string
+	order    binary.ByteOrder
+	varint   bool
+	nameless bool
+	raw      bool
+	limits   Limits
+	count    *countReader
+	reader   Reader
+}
+
+// NewDecoder return a decoder which read the big-endian data from r
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r), order: binary.BigEndian, limits: DefaultLimits()}
+}
+
+// SetCompression set the compression type of the stream rather than detect it,
+// it must be called before the first Decode
+func (d *Decoder) SetCompression(compress string) error {
+	switch compress {
+	case CompressGZIP, CompressZLIB, CompressNone:
+	default:
+		return ErrUnsupportedCompression
+	}
+	if d.reader != nil {
+		return errors.New(errorStreamStarted)
+	}
+	d.compress = compress
+	return nil
+}
+
+// SetByteOrder set the byte order of the stream, binary.LittleEndian for the bedrock edition.
+// It must be called before the first Decode
+func (d *Decoder) SetByteOrder(order binary.ByteOrder) error {
+	if d.reader != nil {
+		return errors.New(errorStreamStarted)
+	}
+	d.order = order
+	return nil
+}
+
+// SetVarint enable the varint encoding of the bedrock network protocol, the byte order
+// is ignored. It must be called before the first Decode
+func (d *Decoder) SetVarint(enable bool) error {
+	if d.reader != nil {
+		return errors.New(errorStreamStarted)
+	}
+	d.varint = enable
+	return nil
+}
+
+// SetNamelessRoot read the root tags without name, like the java network protocol
+// since the 1.20.2. It must be called before the first Decode
+func (d *Decoder) SetNamelessRoot(enable bool) error {
+	if d.reader != nil {
+		return errors.New(errorStreamStarted)
+	}
+	d.nameless = enable
+	return nil
+}
+
+// SetRawStrings keep the bytes of the strings which are not valid modified UTF-8
+// rather than return an error. It must be called before the first Decode
+func (d *Decoder) SetRawStrings(enable bool) error {
+	if d.reader != nil {
+		return errors.New(errorStreamStarted)
+	}
+	d.raw = enable
+	return nil
+}
+
+// SetLimits replace the DefaultLimits of the decoder, the limits which are exceeded
+// return the errors ErrMaxDepth, ErrMaxBytes, ErrMaxLength or ErrMaxInflated.
+// It must be called before the first Decode
+func (d *Decoder) SetLimits(limits Limits) error {
+	if d.reader != nil {
+		return errors.New(errorStreamStarted)
+	}
+	d.limits = limits
+	return nil
+}
+
+// init the reader with the decompression of the stream
+func (d *Decoder) init() error {
+	var err error
+	var driver io.Reader
+
+	compress := d.compress
+	if compress == "" {
+		// a stream shorter than a compression header is not compressed
+		header, _ := d.r.Peek(2)
+		compress = detectCompression(header)
+	}
+	switch compress {
+	case CompressGZIP:
+		if driver, err = gzip.NewReader(d.r); err != nil {
+			return err
+		}
+	case CompressZLIB:
+		if driver, err = zlib.NewReader(d.r); err != nil {
+			return err
+		}
+	default:
+		driver = d.r
+	}
+	if compress != CompressNone {
+		driver = &countReader{r: driver, limit: d.limits.MaxInflated, err: ErrMaxInflated}
+	}
+	d.count = &countReader{r: driver, err: ErrMaxBytes}
+	if d.varint {
+		d.reader = &varintReader{
+			flux:    d.count,
+			limiter: limiter{limits: d.limits},
+		}
+	} else {
+		d.reader = &reader{
+			flux:    d.count,
+			order:   d.order,
+			mutf8:   d.order == binary.BigEndian,
+			raw:     d.raw,
+			limiter: limiter{limits: d.limits},
+		}
+	}
+	return nil
+}
+
+// Decode return the next root tag of the stream, or io.EOF at the end of the stream.
+// A root TAG_End return a nil tag.
+func (d *Decoder) Decode() (Tag, error) {
+	var err error
+	var t Tag
+
+	if d.reader == nil {
+		if err = d.init(); err != nil {
+			return nil, err
+		}
+	}
+	if d.limits.MaxBytes > 0 {
+		d.count.limit = d.count.n + d.limits.MaxBytes
+	}
+	if t, err = readRoot(d.reader, d.nameless); err != nil {
+		if decodeErr, ok := err.(*DecodeError); ok {
+			decodeErr.Offset = d.count.n
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// readRoot return the next root tag of reader, or io.EOF at the end of data.
+// A root TAG_End return a nil tag
+func readRoot(reader Reader, nameless bool) (Tag, error) {
+	var err error
+	var tagT byte
+	var name string
+	var t Tag
+
+	if tagT, err = reader.Byte(); err != nil {
+		return nil, err
+	}
+	if tagT == TagEnd {
+		return nil, nil
+	}
+	if !nameless {
+		if name, err = reader.String(); err != nil {
+			return nil, decodeError(err, "", tagT)
+		}
+	}
+	if t, err = NewTag(tagT, name); err != nil {
+		return nil, decodeError(err, "", tagT)
+	}
+	if err = t.Read(reader); err != nil {
+		return nil, decodeError(err, "", tagT)
+	}
+	return t, nil
+}
+
+// countReader count the bytes read to locate the errors, and return err
+// when the count reach the limit. A limit of 0 disables it
+type countReader struct {
+	r     io.Reader
+	n     int64
+	limit int64
+	err   error
+}
+
+func (c *countReader) Read(p []byte) (int, error) {
+	if c.limit > 0 {
+		if c.n >= c.limit {
+			return 0, c.err
+		}
+		if int64(len(p)) > c.limit-c.n {
+			p = p[:c.limit-c.n]
+		}
+	}
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Encoder write the nbt tags in an output stream
+type Encoder struct {
+	w        io.Writer
+	compress string
+	level    int
+	order    binary.ByteOrder
+	varint   bool
+	nameless bool
+	cw       compressor
+	buffered *BufferedWriter
+	out      *bufio.Writer
+	writer   Writer
+	closed   bool
+	// err is the first error of a tag partially written, the stream is corrupted
+	err error
+}
+
+// NewEncoder return an encoder which write uncompressed big-endian data to w
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, compress: CompressNone, level: BestSpeed, order: binary.BigEndian}
+}
+
+// SetCompression set the compression type and level of the stream,
+// it must be called before the first Encode
+func (e *Encoder) SetCompression(compress string, level int) error {
+	switch compress {
+	case CompressGZIP, CompressZLIB, CompressNone:
+	default:
+		return ErrUnsupportedCompression
+	}
+	if e.writer != nil {
+		return errors.New(errorStreamStarted)
+	}
+	e.compress = compress
+	e.level = level
+	return nil
+}
+
+// SetByteOrder set the byte order of the stream, binary.LittleEndian for the bedrock edition.
+// It must be called before the first Encode
+func (e *Encoder) SetByteOrder(order binary.ByteOrder) error {
+	if e.writer != nil {
+		return errors.New(errorStreamStarted)
+	}
+	e.order = order
+	return nil
+}
+
+// SetVarint enable the varint encoding of the bedrock network protocol, the byte order
+// is ignored. It must be called before the first Encode
+func (e *Encoder) SetVarint(enable bool) error {
+	if e.writer != nil {
+		return errors.New(errorStreamStarted)
+	}
+	e.varint = enable
+	return nil
+}
+
+// SetNamelessRoot write the root tags without name, like the java network protocol
+// since the 1.20.2. It must be called before the first Encode
+func (e *Encoder) SetNamelessRoot(enable bool) error {
+	if e.writer != nil {
+		return errors.New(errorStreamStarted)
+	}
+	e.nameless = enable
+	return nil
+}
+
+// init the writer with the compression of the stream
+func (e *Encoder) init() error {
+	var err error
+
+	driver := e.w
+	if e.compress != CompressNone {
+		if e.cw, err = getCompressor(e.w, e.compress, e.level); err != nil {
+			return err
+		}
+		driver = e.cw
+	}
+	if e.varint {
+		e.out = bufio.NewWriter(driver)
+		e.writer = NewVarintWriter(e.out)
+	} else {
+		e.buffered = getBufferedWriter(driver, e.order)
+		e.writer = e.buffered
+	}
+	return nil
+}
+
+// flush the buffered data to the compressor or the output
+func (e *Encoder) flush() error {
+	if e.buffered != nil {
+		return e.buffered.Flush()
+	}
+	return e.out.Flush()
+}
+
+// Encode write the root tag t in the stream. Once a tag fails to be written,
+// its first bytes may be in the stream and Encode return the same error
+func (e *Encoder) Encode(t Tag) error {
+	var err error
+
+	if e.closed {
+		return errors.New(errorStreamClosed)
+	}
+	if e.err != nil {
+		return e.err
+	}
+	if e.writer == nil {
+		if err = e.init(); err != nil {
+			return err
+		}
+	}
+	if e.nameless {
+		var tagT byte
+
+		if tagT, err = TagType(t); err != nil {
+			return err
+		}
+		if err = e.writer.Byte(tagT); err == nil {
+			err = t.Write(e.writer, false)
+		}
+	} else {
+		err = t.Write(e.writer, true)
+	}
+	if err == nil {
+		err = e.flush()
+	}
+	e.err = err
+	return err
+}
+
+// Close write the end of the compressed stream and release the buffers of the encoder,
+// it doesn't close the underlying writer
+func (e *Encoder) Close() error {
+	var err error
+
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	if e.buffered != nil {
+		putBufferedWriter(e.buffered)
+		e.buffered = nil
+	}
+	if e.cw == nil {
+		return nil
+	}
+	if err = e.cw.Close(); err != nil {
+		return err
+	}
+	putCompressor(e.cw, e.compress, e.level)
+	e.cw = nil
+	return nil
+}
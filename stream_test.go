@@ -0,0 +1,230 @@
+package gonbt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// oneByteReader return one byte on each Read like a slow network stream
+type oneByteReader struct {
+	r io.Reader
+}
+
+func (o oneByteReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	return o.r.Read(p[:1])
+}
+
+func TestDecoder(t *testing.T) {
+	t.Run("should return io.EOF because the stream is empty", func(t *testing.T) {
+		tag, err := NewDecoder(bytes.NewReader([]byte{})).Decode()
+		assert.Equal(t, io.EOF, err)
+		assert.Nil(t, tag)
+	})
+	t.Run("should return an error because the compression type is unknown", func(t *testing.T) {
+		err := NewDecoder(bytes.NewReader([]byte{})).SetCompression("lz4")
+		if assert.Error(t, err) {
+			assert.EqualValues(t, errorCompressType, err.Error())
+		}
+	})
+	t.Run("should return an error because the stream is started", func(t *testing.T) {
+		decoder := NewDecoder(bytes.NewReader([]byte{TagEnd}))
+		_, err := decoder.Decode()
+		if assert.NoError(t, err) {
+			err = decoder.SetCompression(CompressNone)
+			if assert.Error(t, err) {
+				assert.EqualValues(t, errorStreamStarted, err.Error())
+			}
+		}
+	})
+	t.Run("should return an error because the byte order is set after the first decode", func(t *testing.T) {
+		decoder := NewDecoder(bytes.NewReader([]byte{TagEnd}))
+		_, err := decoder.Decode()
+		if assert.NoError(t, err) {
+			err = decoder.SetByteOrder(binary.LittleEndian)
+			if assert.Error(t, err) {
+				assert.EqualValues(t, errorStreamStarted, err.Error())
+			}
+		}
+	})
+	t.Run("should return a decode error with the path and the offset", func(t *testing.T) {
+		tag := &CompoundT{}
+		player := &CompoundT{}
+		player.Set("Inventory", &ListT{Value: []interface{}{
+			&CompoundT{Value: map[string]interface{}{"Count": &ByteT{Value: 1}}},
+			&CompoundT{Value: map[string]interface{}{"Count": &IntT{Value: 1}}},
+		}})
+		tag.Set("Player", player)
+		data, err := Marshal(tag, CompressNone)
+		if assert.NoError(t, err) {
+			_, err = NewDecoder(bytes.NewReader(data[:len(data)-5])).Decode()
+			var decodeErr *DecodeError
+			if assert.True(t, errors.As(err, &decodeErr)) {
+				assert.EqualValues(t, "Player.Inventory[1].Count", decodeErr.Path)
+				assert.EqualValues(t, TagInt, decodeErr.Tag)
+				assert.EqualValues(t, len(data)-5, decodeErr.Offset)
+				assert.True(t, errors.Is(err, ErrTruncated))
+				assert.EqualValues(t, "decode TAG_Int at Player.Inventory[1].Count, offset 49: "+errorTruncated, err.Error())
+			}
+		}
+	})
+	t.Run("should return an error because the tag type is unknown", func(t *testing.T) {
+		_, err := NewDecoder(bytes.NewReader([]byte{TagCompound, 0x00, 0x00, 0x2a, 0x00, 0x01, 'a'})).Decode()
+		var decodeErr *DecodeError
+		if assert.True(t, errors.As(err, &decodeErr)) {
+			assert.EqualValues(t, "a", decodeErr.Path)
+			assert.EqualValues(t, 7, decodeErr.Offset)
+			assert.True(t, errors.Is(err, ErrUnknownTagType))
+		}
+	})
+	t.Run("should return a nil tag with a root TAG_End", func(t *testing.T) {
+		tag, err := NewDecoder(bytes.NewReader([]byte{TagEnd})).Decode()
+		if assert.NoError(t, err) {
+			assert.Nil(t, tag)
+		}
+	})
+	t.Run("should be ok with several root tags on all the compressions", func(t *testing.T) {
+		for _, compress := range []string{CompressGZIP, CompressZLIB, CompressNone} {
+			var buf bytes.Buffer
+			encoder := NewEncoder(&buf)
+			assert.NoError(t, encoder.SetCompression(compress, BestSpeed))
+			assert.NoError(t, encoder.Encode(newTestTag()))
+			assert.NoError(t, encoder.Encode(&IntT{Name: "second", Value: 2}))
+			assert.NoError(t, encoder.Close())
+
+			decoder := NewDecoder(oneByteReader{r: &buf})
+			tag, err := decoder.Decode()
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, newTestTag(), tag)
+			}
+			tag, err = decoder.Decode()
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, &IntT{Name: "second", Value: 2}, tag)
+			}
+			_, err = decoder.Decode()
+			assert.Equal(t, io.EOF, err)
+		}
+	})
+	t.Run("should be ok with the nameless root of the network", func(t *testing.T) {
+		data := []byte{TagCompound, TagByte, 0x00, 0x01, 'a', 0x01, TagEnd, TagString, 0x00, 0x02, 'h', 'i'}
+		expectedTag := &CompoundT{Value: map[string]interface{}{
+			"a": &ByteT{Name: "a", Value: 1},
+		}, Order: []string{"a"}}
+
+		decoder := NewDecoder(bytes.NewReader(data))
+		assert.NoError(t, decoder.SetNamelessRoot(true))
+		tag, err := decoder.Decode()
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, expectedTag, tag)
+		}
+		tag, err = decoder.Decode()
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, &StringT{Value: "hi"}, tag)
+		}
+	})
+	t.Run("should return an error because the string is malformed", func(t *testing.T) {
+		data := []byte{TagString, 0x00, 0x00, 0x00, 0x03, 0xed, 0xa0, 0xbd}
+
+		_, err := NewDecoder(bytes.NewReader(data)).Decode()
+		if assert.Error(t, err) {
+			assert.EqualValues(t, "decode TAG_String, offset 8: "+errorMUTF8, err.Error())
+		}
+	})
+	t.Run("should be ok with the raw bytes of the malformed string", func(t *testing.T) {
+		data := []byte{TagString, 0x00, 0x00, 0x00, 0x03, 0xed, 0xa0, 0xbd}
+
+		decoder := NewDecoder(bytes.NewReader(data))
+		assert.NoError(t, decoder.SetRawStrings(true))
+		tag, err := decoder.Decode()
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, &StringT{Value: "\xed\xa0\xbd"}, tag)
+		}
+	})
+	t.Run("should be ok with a forced compression", func(t *testing.T) {
+		data, err := Marshal(newTestTag(), CompressZLIB)
+		if assert.NoError(t, err) {
+			decoder := NewDecoder(bytes.NewReader(data))
+			assert.NoError(t, decoder.SetCompression(CompressZLIB))
+			tag, err := decoder.Decode()
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, newTestTag(), tag)
+			}
+		}
+	})
+}
+
+func TestEncoder(t *testing.T) {
+	t.Run("should return an error because the compression type is unknown", func(t *testing.T) {
+		err := NewEncoder(&bytes.Buffer{}).SetCompression("lz4", BestSpeed)
+		if assert.Error(t, err) {
+			assert.EqualValues(t, errorCompressType, err.Error())
+		}
+	})
+	t.Run("should return an error because the level is invalid", func(t *testing.T) {
+		encoder := NewEncoder(&bytes.Buffer{})
+		assert.NoError(t, encoder.SetCompression(CompressGZIP, 42))
+		assert.Error(t, encoder.Encode(newTestTag()))
+	})
+	t.Run("should return an error because the stream is started", func(t *testing.T) {
+		encoder := NewEncoder(&bytes.Buffer{})
+		if assert.NoError(t, encoder.Encode(newTestTag())) {
+			err := encoder.SetCompression(CompressGZIP, BestSpeed)
+			if assert.Error(t, err) {
+				assert.EqualValues(t, errorStreamStarted, err.Error())
+			}
+		}
+	})
+	t.Run("should be ok with the little-endian byte order", func(t *testing.T) {
+		var buf bytes.Buffer
+		encoder := NewEncoder(&buf)
+
+		assert.NoError(t, encoder.SetByteOrder(binary.LittleEndian))
+		if assert.NoError(t, encoder.Encode(&ShortT{Name: "a", Value: 0x0102})) {
+			assert.EqualValues(t, []byte{TagShort, 0x01, 0x00, 'a', 0x02, 0x01}, buf.Bytes())
+		}
+	})
+	t.Run("should return an error because the tag is not supported", func(t *testing.T) {
+		encoder := NewEncoder(&bytes.Buffer{})
+		assert.NoError(t, encoder.SetNamelessRoot(true))
+		err := encoder.Encode(&fakeTag{})
+		if assert.Error(t, err) {
+			assert.EqualValues(t, errorTag, err.Error())
+		}
+	})
+	t.Run("should be ok with the nameless root of the network", func(t *testing.T) {
+		var buf bytes.Buffer
+		encoder := NewEncoder(&buf)
+
+		assert.NoError(t, encoder.SetNamelessRoot(true))
+		if assert.NoError(t, encoder.Encode(&CompoundT{Name: "ignored", Value: map[string]interface{}{
+			"a": &ByteT{Value: 1},
+		}})) {
+			assert.EqualValues(t, []byte{TagCompound, TagByte, 0x00, 0x01, 'a', 0x01, TagEnd}, buf.Bytes())
+		}
+	})
+	t.Run("should return the first error after a tag partially written", func(t *testing.T) {
+		var buf bytes.Buffer
+		encoder := NewEncoder(&buf)
+		list := &ListT{Name: "l", Value: []interface{}{&ByteT{Value: 1}, &IntT{Value: 2}}}
+
+		assert.Equal(t, ErrListType, encoder.Encode(&CompoundT{Value: map[string]interface{}{"l": list}}))
+		assert.Equal(t, ErrListType, encoder.Encode(&ByteT{Name: "a", Value: 1}))
+		assert.Empty(t, buf.Bytes())
+	})
+	t.Run("should be ok and write each tag without compression", func(t *testing.T) {
+		var buf bytes.Buffer
+		encoder := NewEncoder(&buf)
+
+		if assert.NoError(t, encoder.Encode(&ByteT{Name: "a", Value: 1})) {
+			assert.EqualValues(t, []byte{TagByte, 0x00, 0x01, 'a', 0x01}, buf.Bytes())
+			assert.NoError(t, encoder.Close())
+		}
+	})
+}
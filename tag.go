@@ -21,6 +21,31 @@ const (
 	TagLongArray
 )
 
+// tagNames to display the tag types
+var tagNames = map[byte]string{
+	TagEnd:       "TAG_End",
+	TagByte:      "TAG_Byte",
+	TagShort:     "TAG_Short",
+	TagInt:       "TAG_Int",
+	TagLong:      "TAG_Long",
+	TagFloat:     "TAG_Float",
+	TagDouble:    "TAG_Double",
+	TagByteArray: "TAG_Byte_Array",
+	TagString:    "TAG_String",
+	TagList:      "TAG_List",
+	TagCompound:  "TAG_Compound",
+	TagIntArray:  "TAG_Int_Array",
+	TagLongArray: "TAG_Long_Array",
+}
+
+// tagName return the name of the tag type
+func tagName(tagT byte) string {
+	if name, ok := tagNames[tagT]; ok {
+		return name
+	}
+	return "TAG_Unknown"
+}
+
 // Tag interface to provide a nbt reader / writer
 type Tag interface {
 	Read(reader Reader) error
@@ -57,7 +82,37 @@ func NewTag(tagT byte, name string) (Tag, error) {
 	case TagLongArray:
 		return &LongArrayT{Name: name}, nil
 	default:
-		return nil, errors.New(errorTag)
+		return nil, ErrUnknownTagType
+	}
+}
+
+// setName set the name of the tag t
+func setName(t Tag, name string) {
+	switch tag := t.(type) {
+	case *ByteT:
+		tag.Name = name
+	case *ShortT:
+		tag.Name = name
+	case *IntT:
+		tag.Name = name
+	case *LongT:
+		tag.Name = name
+	case *FloatT:
+		tag.Name = name
+	case *DoubleT:
+		tag.Name = name
+	case *ByteArrayT:
+		tag.Name = name
+	case *StringT:
+		tag.Name = name
+	case *ListT:
+		tag.Name = name
+	case *CompoundT:
+		tag.Name = name
+	case *IntArrayT:
+		tag.Name = name
+	case *LongArrayT:
+		tag.Name = name
 	}
 }
 
@@ -89,7 +144,7 @@ func TagType(tag Tag) (byte, error) {
 	case *LongArrayT:
 		return TagLongArray, nil
 	default:
-		return byte('0'), errors.New(errorTag)
+		return byte('0'), ErrUnknownTagType
 	}
 }
 
@@ -146,14 +201,20 @@ type StringT struct {
 
 // ListT to list type: 9
 type ListT struct {
-	Name  string
-	Value []interface{}
+	Name string
+	// ElemType is the tag type of the elements, set on Read. When it's TagEnd,
+	// the type of the first element is written
+	ElemType byte
+	Value    []interface{}
 }
 
 // CompoundT to compound type: 10
 type CompoundT struct {
 	Name  string
 	Value map[string]interface{}
+	// Order of the Value elements, set on Read to keep the order of the data.
+	// The elements which are not in Order are written after, sorted by name.
+	Order []string
 }
 
 // IntArrayT to int array type: 11
@@ -396,22 +457,30 @@ func (t *ListT) Read(reader Reader) error {
 	var tagT byte
 	var nbr int32
 
+	if err = enter(reader); err != nil {
+		return decodeError(err, "", TagList)
+	}
+	defer leave(reader)
 	// get the the tagType
 	if tagT, err = reader.Byte(); err != nil {
-		return err
+		return decodeError(err, "", TagList)
 	}
 	// get number element
 	if nbr, err = reader.Int(); err != nil {
-		return err
+		return decodeError(err, "", TagList)
+	}
+	if err = checkLength(reader, nbr); err != nil {
+		return decodeError(err, "", TagList)
 	}
+	t.ElemType = tagT
 
 	for i := int32(0); i < nbr; i++ {
 		var elem Tag
 		if elem, err = NewTag(tagT, ""); err != nil {
-			return err
+			return decodeError(err, indexPath("", int(i)), tagT)
 		}
 		if err = elem.Read(reader); err != nil {
-			return err
+			return decodeError(err, indexPath("", int(i)), tagT)
 		}
 		t.Value = append(t.Value, elem)
 	}
@@ -433,13 +502,8 @@ func (t *ListT) Write(writer Writer, printInfo bool) error {
 	}
 
 	nbr = int32(len(t.Value))
-	if nbr > 0 {
-		if _, ok := t.Value[0].(Tag); !ok {
-			return errors.New(errorTag)
-		}
-		if tagT, err = TagType(t.Value[0].(Tag)); err != nil {
-			return err
-		}
+	if tagT, err = t.elemType(); err != nil {
+		return err
 	}
 	if err = writer.Byte(tagT); err != nil {
 		return err
@@ -456,28 +520,65 @@ func (t *ListT) Write(writer Writer, printInfo bool) error {
 	return nil
 }
 
+// elemType return the type of the elements to write, and an error
+// if an element doesn't match it
+func (t *ListT) elemType() (byte, error) {
+	var err error
+	var tagT byte
+
+	for i, value := range t.Value {
+		var elemT byte
+
+		elem, ok := value.(Tag)
+		if !ok {
+			return TagEnd, ErrUnknownTagType
+		}
+		if elemT, err = TagType(elem); err != nil {
+			return TagEnd, err
+		}
+		if i == 0 {
+			tagT = elemT
+			if t.ElemType != TagEnd && t.ElemType != tagT {
+				return TagEnd, ErrListType
+			}
+		} else if elemT != tagT {
+			return TagEnd, ErrListType
+		}
+	}
+	if len(t.Value) == 0 {
+		tagT = t.ElemType
+	}
+	return tagT, nil
+}
+
 // 10 		TAG_Compound 	Fully formed tags, followed by a TAG_End. 	{<tag name>:<value>,<tag name>:<value>,...} 	A list of fully formed tags, including their IDs, names, and payloads. No two tags may have the same name. 	Unlike lists, there is no hard limit to the number of tags within a Compound (of course, there is always the implicit limit of virtual memory). Note, however, that Compound and List tags may not be nested beyond a depth of 512.
 func (t *CompoundT) Read(reader Reader) error {
 	var err error
 	var tagT byte
 	var name string
 
+	if err = enter(reader); err != nil {
+		return decodeError(err, "", TagCompound)
+	}
+	defer leave(reader)
 	t.Value = make(map[string]interface{})
+	t.Order = nil
 	for tagT, err = reader.Byte(); tagT != TagEnd && err == nil; tagT, err = reader.Byte() {
 		if name, err = reader.String(); err != nil {
-			return err
+			return decodeError(err, "", TagCompound)
 		}
 		var elem Tag
 		if elem, err = NewTag(tagT, name); err != nil {
-			return err
+			return decodeError(err, name, tagT)
 		}
 		if err = elem.Read(reader); err != nil {
-			return err
+			return decodeError(err, name, tagT)
 		}
 		t.Value[name] = elem
+		t.Order = append(t.Order, name)
 	}
 	if err != nil {
-		return err
+		return decodeError(err, "", TagCompound)
 	}
 	return nil
 }
@@ -493,11 +594,12 @@ func (t *CompoundT) Write(writer Writer, printInfo bool) error {
 			return err
 		}
 	}
-	for key, value := range t.Value {
+	for _, key := range t.Keys() {
 		var tagT byte
 
+		value := t.Value[key]
 		if _, ok := value.(Tag); !ok {
-			return errors.New(errorTag)
+			return ErrUnknownTagType
 		}
 		if tagT, err = TagType(value.(Tag)); err != nil {
 			return err
@@ -539,18 +641,13 @@ func (t *IntArrayT) Write(writer Writer, printInfo bool) error {
 			return err
 		}
 	}
-	if err = writer.Int(int32(len(t.Value))); err != nil {
+	if err = writer.IntArray(t.Value); err != nil {
 		return err
 	}
-	for _, v := range t.Value {
-		if err = writer.Int(v); err != nil {
-			return err
-		}
-	}
 	return nil
 }
 
-// 12 		TAG_Long_Array 	TAG_
+// 12 		TAG_Long_Array 	TAG_Int's payload size, then size TAG_Long's payloads. 	[L;<long>,<long>,...] 	An array of TAG_Long's payloads. 	Maximum number of elements ranges between (231 - 9) and (231 - 1) (2,147,483,639 and 2,147,483,647), depending on the specific JVM.
 func (t *LongArrayT) Read(reader Reader) error {
 	var err error
 
@@ -571,13 +668,8 @@ func (t *LongArrayT) Write(writer Writer, printInfo bool) error {
 			return err
 		}
 	}
-	if err = writer.Long(int64(len(t.Value))); err != nil {
+	if err = writer.LongArray(t.Value); err != nil {
 		return err
 	}
-	for _, v := range t.Value {
-		if err = writer.Long(v); err != nil {
-			return err
-		}
-	}
 	return nil
 }
@@ -881,7 +881,7 @@ func TestListT_Read(t *testing.T) {
 		mreader.EXPECT().Byte().Return(byte('0'), errors.New(expectedMockErr))
 		err := tag.Read(mreader)
 		if assert.Error(t, err) {
-			assert.EqualValues(t, expectedMockErr, err.Error())
+			assert.EqualValues(t, expectedMockErr, errors.Unwrap(err).Error())
 			assert.Empty(t, tag.Value)
 		}
 	})
@@ -892,7 +892,7 @@ func TestListT_Read(t *testing.T) {
 		mreader.EXPECT().Int().Return(int32(0), errors.New(expectedMockErr))
 		err := tag.Read(mreader)
 		if assert.Error(t, err) {
-			assert.EqualValues(t, expectedMockErr, err.Error())
+			assert.EqualValues(t, expectedMockErr, errors.Unwrap(err).Error())
 			assert.Empty(t, tag.Value)
 		}
 	})
@@ -903,7 +903,7 @@ func TestListT_Read(t *testing.T) {
 		mreader.EXPECT().Int().Return(int32(3), nil)
 		err := tag.Read(mreader)
 		if assert.Error(t, err) {
-			assert.EqualValues(t, errorTag, err.Error())
+			assert.True(t, errors.Is(err, ErrUnknownTagType))
 			assert.Empty(t, tag.Value)
 		}
 	})
@@ -915,7 +915,7 @@ func TestListT_Read(t *testing.T) {
 		mreader.EXPECT().String().Return("", errors.New(expectedMockErr))
 		err := tag.Read(mreader)
 		if assert.Error(t, err) {
-			assert.EqualValues(t, expectedMockErr, err.Error())
+			assert.EqualValues(t, expectedMockErr, errors.Unwrap(err).Error())
 			assert.Empty(t, tag.Value)
 		}
 	})
@@ -931,6 +931,18 @@ func TestListT_Read(t *testing.T) {
 		err := tag.Read(mreader)
 		if assert.NoError(t, err) {
 			assert.EqualValues(t, expectedValue, tag.Value)
+			assert.EqualValues(t, TagString, tag.ElemType)
+		}
+	})
+	t.Run("Should be ok and keep the type of an empty list", func(t *testing.T) {
+		tag := &ListT{}
+
+		mreader.EXPECT().Byte().Return(byte(TagCompound), nil)
+		mreader.EXPECT().Int().Return(int32(0), nil)
+		err := tag.Read(mreader)
+		if assert.NoError(t, err) {
+			assert.Empty(t, tag.Value)
+			assert.EqualValues(t, TagCompound, tag.ElemType)
 		}
 	})
 }
@@ -1041,6 +1053,32 @@ func TestListT_Write(t *testing.T) {
 		err := tag.Write(mwriter, true)
 		assert.NoError(t, err)
 	})
+	t.Run("Should be ok with the element type of an empty list", func(t *testing.T) {
+		tag := &ListT{Name: tagName, ElemType: TagCompound}
+
+		mwriter.EXPECT().Byte(gomock.Eq(TagList)).Return(nil)
+		mwriter.EXPECT().String(gomock.Eq(tagName)).Return(nil)
+		mwriter.EXPECT().Byte(gomock.Eq(TagCompound)).Return(nil)
+		mwriter.EXPECT().Int(gomock.Eq(int32(0))).Return(nil)
+		err := tag.Write(mwriter, true)
+		assert.NoError(t, err)
+	})
+	t.Run("Should return an error because an element doesn't match the element type", func(t *testing.T) {
+		tag := &ListT{Name: tagName, ElemType: TagString, Value: []interface{}{&StringT{Value: "coucou"}, &IntT{Value: 1}}}
+
+		mwriter.EXPECT().Byte(gomock.Eq(TagList)).Return(nil)
+		mwriter.EXPECT().String(gomock.Eq(tagName)).Return(nil)
+		err := tag.Write(mwriter, true)
+		assert.Equal(t, ErrListType, err)
+	})
+	t.Run("Should return an error because the first element doesn't match the element type", func(t *testing.T) {
+		tag := &ListT{Name: tagName, ElemType: TagInt, Value: []interface{}{&StringT{Value: "coucou"}}}
+
+		mwriter.EXPECT().Byte(gomock.Eq(TagList)).Return(nil)
+		mwriter.EXPECT().String(gomock.Eq(tagName)).Return(nil)
+		err := tag.Write(mwriter, true)
+		assert.Equal(t, ErrListType, err)
+	})
 }
 
 func TestCompoundT_Read(t *testing.T) {
@@ -1056,7 +1094,7 @@ func TestCompoundT_Read(t *testing.T) {
 		mreader.EXPECT().Byte().Return(byte('0'), errors.New(expectedMockErr))
 		err := tag.Read(mreader)
 		if assert.Error(t, err) {
-			assert.EqualValues(t, expectedMockErr, err.Error())
+			assert.EqualValues(t, expectedMockErr, errors.Unwrap(err).Error())
 			assert.EqualValues(t, expectedValue, tag.Value)
 		}
 	})
@@ -1069,7 +1107,7 @@ func TestCompoundT_Read(t *testing.T) {
 		mreader.EXPECT().String().Return("", errors.New(expectedMockErr))
 		err := tag.Read(mreader)
 		if assert.Error(t, err) {
-			assert.EqualValues(t, expectedMockErr, err.Error())
+			assert.EqualValues(t, expectedMockErr, errors.Unwrap(err).Error())
 			assert.EqualValues(t, expectedValue, tag.Value)
 		}
 	})
@@ -1081,7 +1119,7 @@ func TestCompoundT_Read(t *testing.T) {
 		mreader.EXPECT().String().Return("tag_name", nil)
 		err := tag.Read(mreader)
 		if assert.Error(t, err) {
-			assert.EqualValues(t, errorTag, err.Error())
+			assert.True(t, errors.Is(err, ErrUnknownTagType))
 			assert.EqualValues(t, expectedValue, tag.Value)
 		}
 	})
@@ -1094,7 +1132,7 @@ func TestCompoundT_Read(t *testing.T) {
 		mreader.EXPECT().String().Return("", errors.New(expectedMockErr))
 		err := tag.Read(mreader)
 		if assert.Error(t, err) {
-			assert.EqualValues(t, expectedMockErr, err.Error())
+			assert.EqualValues(t, expectedMockErr, errors.Unwrap(err).Error())
 			assert.EqualValues(t, expectedValue, tag.Value)
 		}
 	})
@@ -1125,6 +1163,7 @@ func TestCompoundT_Read(t *testing.T) {
 		err := tag.Read(mreader)
 		if assert.NoError(t, err) {
 			assert.EqualValues(t, expectedValue, tag.Value)
+			assert.EqualValues(t, []string{"tag_name1", "tag_name2"}, tag.Order)
 		}
 	})
 
@@ -1331,24 +1370,12 @@ func TestIntArrayT_Write(t *testing.T) {
 			assert.EqualValues(t, expectedMockErr, err.Error())
 		}
 	})
-	t.Run("Should return an error because the first call to writer.Int failed", func(t *testing.T) {
+	t.Run("Should return an error because the call to writer.IntArray failed", func(t *testing.T) {
 		tag := &IntArrayT{Name: tagName}
 
 		mwriter.EXPECT().Byte(gomock.Eq(TagIntArray)).Return(nil)
 		mwriter.EXPECT().String(gomock.Eq(tagName)).Return(nil)
-		mwriter.EXPECT().Int(gomock.Eq(int32(0))).Return(errors.New(expectedMockErr))
-		err := tag.Write(mwriter, true)
-		if assert.Error(t, err) {
-			assert.EqualValues(t, expectedMockErr, err.Error())
-		}
-	})
-	t.Run("Should return an error because the second call to writer.Int failed", func(t *testing.T) {
-		tag := &IntArrayT{Name: tagName, Value: []int32{42}}
-
-		mwriter.EXPECT().Byte(gomock.Eq(TagIntArray)).Return(nil)
-		mwriter.EXPECT().String(gomock.Eq(tagName)).Return(nil)
-		mwriter.EXPECT().Int(gomock.Eq(int32(1))).Return(nil)
-		mwriter.EXPECT().Int(gomock.Eq(int32(42))).Return(errors.New(expectedMockErr))
+		mwriter.EXPECT().IntArray(gomock.Eq([]int32(nil))).Return(errors.New(expectedMockErr))
 		err := tag.Write(mwriter, true)
 		if assert.Error(t, err) {
 			assert.EqualValues(t, expectedMockErr, err.Error())
@@ -1360,10 +1387,7 @@ func TestIntArrayT_Write(t *testing.T) {
 
 		mwriter.EXPECT().Byte(gomock.Eq(TagIntArray)).Return(nil)
 		mwriter.EXPECT().String(gomock.Eq(tagName)).Return(nil)
-		mwriter.EXPECT().Int(gomock.Eq(int32(3))).Return(nil)
-		mwriter.EXPECT().Int(gomock.Eq(int32(42))).Return(nil)
-		mwriter.EXPECT().Int(gomock.Eq(int32(3))).Return(nil)
-		mwriter.EXPECT().Int(gomock.Eq(int32(33))).Return(nil)
+		mwriter.EXPECT().IntArray(gomock.Eq(expectedValue)).Return(nil)
 		err := tag.Write(mwriter, true)
 		if assert.NoError(t, err) {
 			assert.EqualValues(t, expectedValue, tag.Value)
@@ -1375,7 +1399,7 @@ func TestIntArrayT_Write(t *testing.T) {
 
 		mwriter.EXPECT().Byte(gomock.Eq(TagIntArray)).Return(nil)
 		mwriter.EXPECT().String(gomock.Eq(tagName)).Return(nil)
-		mwriter.EXPECT().Int(gomock.Eq(int32(0))).Return(nil)
+		mwriter.EXPECT().IntArray(gomock.Eq(expectedValue)).Return(nil)
 		err := tag.Write(mwriter, true)
 		if assert.NoError(t, err) {
 			assert.EqualValues(t, expectedValue, tag.Value)
@@ -1438,24 +1462,12 @@ func TestLongArrayT_Write(t *testing.T) {
 			assert.EqualValues(t, expectedMockErr, err.Error())
 		}
 	})
-	t.Run("Should return an error because the first call to writer.Int failed", func(t *testing.T) {
+	t.Run("Should return an error because the call to writer.LongArray failed", func(t *testing.T) {
 		tag := &LongArrayT{Name: tagName}
 
 		mwriter.EXPECT().Byte(gomock.Eq(TagLongArray)).Return(nil)
 		mwriter.EXPECT().String(gomock.Eq(tagName)).Return(nil)
-		mwriter.EXPECT().Long(gomock.Eq(int64(0))).Return(errors.New(expectedMockErr))
-		err := tag.Write(mwriter, true)
-		if assert.Error(t, err) {
-			assert.EqualValues(t, expectedMockErr, err.Error())
-		}
-	})
-	t.Run("Should return an error because the second call to writer.Int failed", func(t *testing.T) {
-		tag := &LongArrayT{Name: tagName, Value: []int64{42}}
-
-		mwriter.EXPECT().Byte(gomock.Eq(TagLongArray)).Return(nil)
-		mwriter.EXPECT().String(gomock.Eq(tagName)).Return(nil)
-		mwriter.EXPECT().Long(gomock.Eq(int64(1))).Return(nil)
-		mwriter.EXPECT().Long(gomock.Eq(int64(42))).Return(errors.New(expectedMockErr))
+		mwriter.EXPECT().LongArray(gomock.Eq([]int64(nil))).Return(errors.New(expectedMockErr))
 		err := tag.Write(mwriter, true)
 		if assert.Error(t, err) {
 			assert.EqualValues(t, expectedMockErr, err.Error())
@@ -1467,10 +1479,7 @@ func TestLongArrayT_Write(t *testing.T) {
 
 		mwriter.EXPECT().Byte(gomock.Eq(TagLongArray)).Return(nil)
 		mwriter.EXPECT().String(gomock.Eq(tagName)).Return(nil)
-		mwriter.EXPECT().Long(gomock.Eq(int64(3))).Return(nil)
-		mwriter.EXPECT().Long(gomock.Eq(int64(42))).Return(nil)
-		mwriter.EXPECT().Long(gomock.Eq(int64(3))).Return(nil)
-		mwriter.EXPECT().Long(gomock.Eq(int64(33))).Return(nil)
+		mwriter.EXPECT().LongArray(gomock.Eq(expectedValue)).Return(nil)
 		err := tag.Write(mwriter, true)
 		if assert.NoError(t, err) {
 			assert.EqualValues(t, expectedValue, tag.Value)
@@ -1482,7 +1491,7 @@ func TestLongArrayT_Write(t *testing.T) {
 
 		mwriter.EXPECT().Byte(gomock.Eq(TagLongArray)).Return(nil)
 		mwriter.EXPECT().String(gomock.Eq(tagName)).Return(nil)
-		mwriter.EXPECT().Long(gomock.Eq(int64(0))).Return(nil)
+		mwriter.EXPECT().LongArray(gomock.Eq(expectedValue)).Return(nil)
 		err := tag.Write(mwriter, true)
 		if assert.NoError(t, err) {
 			assert.EqualValues(t, expectedValue, tag.Value)
@@ -0,0 +1,207 @@
+package gonbt
+
+// TokenKind of the tokens returned by the Tokenizer
+type TokenKind int
+
+// token kinds
+const (
+	// TokenBeginCompound start a compound, its elements follow until the TokenEnd
+	TokenBeginCompound TokenKind = iota + 1
+	// TokenBeginList start a list of Len elements of type ElemType, followed by a TokenEnd
+	TokenBeginList
+	// TokenValue is a tag which is neither a compound nor a list
+	TokenValue
+	// TokenEnd close the last compound or list
+	TokenEnd
+)
+
+// Token of the nbt data
+type Token struct {
+	Kind TokenKind
+	// Type of the tag, TagEnd for the TokenEnd
+	Type byte
+	// Name of the tag, empty for the elements of a list
+	Name string
+	// ElemType and Len of the TokenBeginList
+	ElemType byte
+	Len      int
+	// Value of the TokenValue: byte, int16, int32, int64, float32, float64,
+	// []byte, string, []int32 or []int64
+	Value interface{}
+}
+
+// frame is a compound or a list opened by the tokenizer
+type frame struct {
+	tagT     byte
+	elemType byte
+	// seg is the segment of the path of the frame
+	seg string
+	// index of the next element and remaining elements of a list
+	index     int
+	remaining int32
+}
+
+// Tokenizer read the nbt data as a sequence of tokens, without building the tags
+type Tokenizer struct {
+	reader Reader
+	stack  []frame
+}
+
+// NewTokenizer return a tokenizer which read the root tags from reader
+func NewTokenizer(reader Reader) *Tokenizer {
+	return &Tokenizer{reader: reader}
+}
+
+// Depth return the number of compounds and lists opened
+func (t *Tokenizer) Depth() int {
+	return len(t.stack)
+}
+
+// Next return the next token, or io.EOF at the end of data. A root TAG_End return
+// ErrRootEnd, the next root tags can still be read
+func (t *Tokenizer) Next() (Token, error) {
+	var err error
+	var tagT byte
+	var name string
+
+	if len(t.stack) == 0 {
+		if tagT, err = t.reader.Byte(); err != nil {
+			return Token{}, err
+		}
+		if tagT == TagEnd {
+			return Token{}, ErrRootEnd
+		}
+		if name, err = t.reader.String(); err != nil {
+			return Token{}, t.error(err, "", tagT)
+		}
+		return t.token(tagT, name, "")
+	}
+	top := &t.stack[len(t.stack)-1]
+	if top.tagT == TagList {
+		if top.remaining == 0 {
+			return t.end(), nil
+		}
+		top.remaining--
+		top.index++
+		return t.token(top.elemType, "", indexPath("", top.index-1))
+	}
+	if tagT, err = t.reader.Byte(); err != nil {
+		return Token{}, t.error(err, "", TagCompound)
+	}
+	if tagT == TagEnd {
+		return t.end(), nil
+	}
+	if name, err = t.reader.String(); err != nil {
+		return Token{}, t.error(err, "", TagCompound)
+	}
+	return t.token(tagT, name, name)
+}
+
+// Skip the rest of the last compound or list opened, including its TokenEnd
+func (t *Tokenizer) Skip() error {
+	var err error
+	var tagT byte
+
+	if len(t.stack) == 0 {
+		return nil
+	}
+	top := &t.stack[len(t.stack)-1]
+	if top.tagT == TagList {
+		for ; top.remaining > 0; top.remaining-- {
+			if err = skipTag(t.reader, top.elemType); err != nil {
+				return t.error(err, indexPath("", top.index), top.elemType)
+			}
+			top.index++
+		}
+		t.end()
+		return nil
+	}
+	for tagT, err = t.reader.Byte(); tagT != TagEnd && err == nil; tagT, err = t.reader.Byte() {
+		var name string
+		if name, err = t.reader.String(); err != nil {
+			return t.error(err, "", TagCompound)
+		}
+		if err = skipTag(t.reader, tagT); err != nil {
+			return t.error(err, name, tagT)
+		}
+	}
+	if err != nil {
+		return t.error(err, "", TagCompound)
+	}
+	t.end()
+	return nil
+}
+
+// token read the payload of the tag tagT named name, seg is its segment of path
+func (t *Tokenizer) token(tagT byte, name string, seg string) (Token, error) {
+	var err error
+	var value interface{}
+
+	switch tagT {
+	case TagCompound:
+		if err = enter(t.reader); err != nil {
+			return Token{}, t.error(err, seg, tagT)
+		}
+		t.stack = append(t.stack, frame{tagT: tagT, seg: seg})
+		return Token{Kind: TokenBeginCompound, Type: tagT, Name: name}, nil
+	case TagList:
+		var elemType byte
+		var n int32
+		if elemType, err = t.reader.Byte(); err != nil {
+			return Token{}, t.error(err, seg, tagT)
+		}
+		if n, err = t.reader.Int(); err != nil {
+			return Token{}, t.error(err, seg, tagT)
+		}
+		if err = checkLength(t.reader, n); err != nil {
+			return Token{}, t.error(err, seg, tagT)
+		}
+		if err = enter(t.reader); err != nil {
+			return Token{}, t.error(err, seg, tagT)
+		}
+		t.stack = append(t.stack, frame{tagT: tagT, elemType: elemType, seg: seg, remaining: n})
+		return Token{Kind: TokenBeginList, Type: tagT, Name: name, ElemType: elemType, Len: int(n)}, nil
+	case TagByte:
+		value, err = t.reader.Byte()
+	case TagShort:
+		value, err = t.reader.Short()
+	case TagInt:
+		value, err = t.reader.Int()
+	case TagLong:
+		value, err = t.reader.Long()
+	case TagFloat:
+		value, err = t.reader.Float()
+	case TagDouble:
+		value, err = t.reader.Double()
+	case TagByteArray:
+		value, err = t.reader.Bytes()
+	case TagString:
+		value, err = t.reader.String()
+	case TagIntArray:
+		value, err = t.reader.IntArray()
+	case TagLongArray:
+		value, err = t.reader.LongArray()
+	default:
+		err = ErrUnknownTagType
+	}
+	if err != nil {
+		return Token{}, t.error(err, seg, tagT)
+	}
+	return Token{Kind: TokenValue, Type: tagT, Name: name, Value: value}, nil
+}
+
+// end close the last compound or list
+func (t *Tokenizer) end() Token {
+	t.stack = t.stack[:len(t.stack)-1]
+	leave(t.reader)
+	return Token{Kind: TokenEnd}
+}
+
+// error return the DecodeError of err at the segment seg of the current path
+func (t *Tokenizer) error(err error, seg string, tagT byte) error {
+	err = decodeError(err, seg, tagT)
+	for i := len(t.stack) - 1; i >= 0; i-- {
+		err = decodeError(err, t.stack[i].seg, t.stack[i].tagT)
+	}
+	return err
+}
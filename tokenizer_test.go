@@ -0,0 +1,225 @@
+package gonbt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTokenizerTag return a compound with a nested compound and a list of compounds
+func newTokenizerTag() *CompoundT {
+	item := &CompoundT{}
+	item.Set("id", &StringT{Value: "minecraft:stone"})
+	item.Set("Count", &ByteT{Value: 3})
+	inventory := &ListT{Value: []interface{}{item}}
+	pos := &ListT{Value: []interface{}{&DoubleT{Value: 1.5}, &DoubleT{Value: -2}}}
+	player := &CompoundT{Name: "Player"}
+	player.Set("Health", &FloatT{Value: 20})
+	player.Set("Pos", pos)
+	player.Set("Inventory", inventory)
+	player.Set("Seeds", &LongArrayT{Value: []int64{1, 2}})
+	return player
+}
+
+// tokens return all the tokens of the tokenizer until io.EOF
+func tokens(tokenizer *Tokenizer) ([]Token, error) {
+	var ret []Token
+	for {
+		token, err := tokenizer.Next()
+		if err == io.EOF {
+			return ret, nil
+		} else if err != nil {
+			return ret, err
+		}
+		ret = append(ret, token)
+	}
+}
+
+func TestTokenizer_Next(t *testing.T) {
+	expected := []Token{
+		{Kind: TokenBeginCompound, Type: TagCompound, Name: "Player"},
+		{Kind: TokenValue, Type: TagFloat, Name: "Health", Value: float32(20)},
+		{Kind: TokenBeginList, Type: TagList, Name: "Pos", ElemType: TagDouble, Len: 2},
+		{Kind: TokenValue, Type: TagDouble, Value: float64(1.5)},
+		{Kind: TokenValue, Type: TagDouble, Value: float64(-2)},
+		{Kind: TokenEnd},
+		{Kind: TokenBeginList, Type: TagList, Name: "Inventory", ElemType: TagCompound, Len: 1},
+		{Kind: TokenBeginCompound, Type: TagCompound},
+		{Kind: TokenValue, Type: TagString, Name: "id", Value: "minecraft:stone"},
+		{Kind: TokenValue, Type: TagByte, Name: "Count", Value: byte(3)},
+		{Kind: TokenEnd},
+		{Kind: TokenEnd},
+		{Kind: TokenValue, Type: TagLongArray, Name: "Seeds", Value: []int64{1, 2}},
+		{Kind: TokenEnd},
+	}
+
+	t.Run("should be ok with the tokens of a reader", func(t *testing.T) {
+		data, err := Marshal(newTokenizerTag(), CompressNone)
+		if !assert.NoError(t, err) {
+			return
+		}
+		ret, err := tokens(NewTokenizer(NewReader(bytes.NewReader(data))))
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, ret)
+		}
+	})
+	t.Run("should be ok with the tokens of a bytes reader", func(t *testing.T) {
+		data, err := MarshalLE(newTokenizerTag(), CompressNone)
+		if !assert.NoError(t, err) {
+			return
+		}
+		ret, err := tokens(NewTokenizer(NewBytesReader(data, binary.LittleEndian)))
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, ret)
+		}
+	})
+	t.Run("should be ok with the tokens of a varint reader", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		encoder := NewEncoder(buf)
+		if !assert.NoError(t, encoder.SetVarint(true)) ||
+			!assert.NoError(t, encoder.Encode(newTokenizerTag())) ||
+			!assert.NoError(t, encoder.Close()) {
+			return
+		}
+		ret, err := tokens(NewTokenizer(NewVarintReader(buf)))
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, ret)
+		}
+	})
+	t.Run("should be ok with several root tags", func(t *testing.T) {
+		data := []byte{TagByte, 0x00, 0x01, 'a', 0x01, TagShort, 0x00, 0x01, 'b', 0x00, 0x02}
+
+		ret, err := tokens(NewTokenizer(NewBytesReader(data, binary.BigEndian)))
+		if assert.NoError(t, err) {
+			assert.Equal(t, []Token{
+				{Kind: TokenValue, Type: TagByte, Name: "a", Value: byte(1)},
+				{Kind: TokenValue, Type: TagShort, Name: "b", Value: int16(2)},
+			}, ret)
+		}
+	})
+	t.Run("should return an error because the root tag is a TAG_End", func(t *testing.T) {
+		tokenizer := NewTokenizer(NewBytesReader([]byte{TagEnd, TagByte, 0x00, 0x01, 'a', 0x01}, binary.BigEndian))
+
+		_, err := tokenizer.Next()
+		assert.Equal(t, ErrRootEnd, err)
+		token, err := tokenizer.Next()
+		if assert.NoError(t, err) {
+			assert.Equal(t, Token{Kind: TokenValue, Type: TagByte, Name: "a", Value: byte(1)}, token)
+		}
+	})
+	t.Run("should return an error with the path because the data is truncated", func(t *testing.T) {
+		data, err := Marshal(newTokenizerTag(), CompressNone)
+		if !assert.NoError(t, err) {
+			return
+		}
+		_, err = tokens(NewTokenizer(NewBytesReader(data[:len(data)-30], binary.BigEndian)))
+		var decodeErr *DecodeError
+		if assert.True(t, errors.As(err, &decodeErr)) {
+			assert.True(t, errors.Is(err, ErrTruncated))
+			assert.Equal(t, "Inventory[0]", decodeErr.Path)
+		}
+	})
+	t.Run("should return an error because the tag type is unknown", func(t *testing.T) {
+		data := []byte{TagCompound, 0x00, 0x00, 0x0d, 0x00, 0x01, 'a'}
+
+		_, err := tokens(NewTokenizer(NewBytesReader(data, binary.BigEndian)))
+		var decodeErr *DecodeError
+		if assert.True(t, errors.As(err, &decodeErr)) {
+			assert.True(t, errors.Is(err, ErrUnknownTagType))
+			assert.Equal(t, "a", decodeErr.Path)
+		}
+	})
+	t.Run("should return an error because the depth exceeds the limit", func(t *testing.T) {
+		tokenizer := NewTokenizer(NewBytesReader(nestedLists(DefaultMaxDepth+1), binary.BigEndian))
+
+		_, err := tokens(tokenizer)
+		if assert.Error(t, err) {
+			assert.True(t, errors.Is(err, ErrMaxDepth))
+			assert.Equal(t, DefaultMaxDepth, tokenizer.Depth())
+		}
+	})
+}
+
+func TestTokenizer_Skip(t *testing.T) {
+	t.Run("should be ok and skip the rest of the compound", func(t *testing.T) {
+		data, err := Marshal(newTokenizerTag(), CompressNone)
+		if !assert.NoError(t, err) {
+			return
+		}
+		tokenizer := NewTokenizer(NewBytesReader(append(data, TagByte, 0x00, 0x00, 0x07), binary.BigEndian))
+		token, err := tokenizer.Next()
+		if assert.NoError(t, err) {
+			assert.Equal(t, TokenBeginCompound, token.Kind)
+		}
+		_, err = tokenizer.Next()
+		assert.NoError(t, err)
+		if assert.NoError(t, tokenizer.Skip()) {
+			assert.Equal(t, 0, tokenizer.Depth())
+		}
+		token, err = tokenizer.Next()
+		if assert.NoError(t, err) {
+			assert.Equal(t, Token{Kind: TokenValue, Type: TagByte, Value: byte(7)}, token)
+		}
+	})
+	t.Run("should be ok and skip the rest of the list", func(t *testing.T) {
+		data, err := Marshal(newTokenizerTag(), CompressNone)
+		if !assert.NoError(t, err) {
+			return
+		}
+		tokenizer := NewTokenizer(NewReader(bytes.NewReader(data)))
+		for i := 0; i < 4; i++ {
+			_, err = tokenizer.Next()
+			assert.NoError(t, err)
+		}
+		if assert.NoError(t, tokenizer.Skip()) {
+			assert.Equal(t, 1, tokenizer.Depth())
+		}
+		token, err := tokenizer.Next()
+		if assert.NoError(t, err) {
+			assert.Equal(t, "Inventory", token.Name)
+		}
+		if assert.NoError(t, tokenizer.Skip()) {
+			assert.Equal(t, 1, tokenizer.Depth())
+		}
+		token, err = tokenizer.Next()
+		if assert.NoError(t, err) {
+			assert.Equal(t, "Seeds", token.Name)
+		}
+	})
+	t.Run("should be ok and skip with a reader which can't skip", func(t *testing.T) {
+		data, err := Marshal(newTokenizerTag(), CompressNone)
+		if !assert.NoError(t, err) {
+			return
+		}
+		tokenizer := NewTokenizer(struct{ Reader }{NewReader(bytes.NewReader(data))})
+		_, err = tokenizer.Next()
+		assert.NoError(t, err)
+		if assert.NoError(t, tokenizer.Skip()) {
+			assert.Equal(t, 0, tokenizer.Depth())
+		}
+		_, err = tokenizer.Next()
+		assert.Equal(t, io.EOF, err)
+	})
+	t.Run("should return an error with the path because the data is truncated", func(t *testing.T) {
+		data, err := Marshal(newTokenizerTag(), CompressNone)
+		if !assert.NoError(t, err) {
+			return
+		}
+		tokenizer := NewTokenizer(NewBytesReader(data[:len(data)-30], binary.BigEndian))
+		_, err = tokenizer.Next()
+		assert.NoError(t, err)
+		err = tokenizer.Skip()
+		var decodeErr *DecodeError
+		if assert.True(t, errors.As(err, &decodeErr)) {
+			assert.True(t, errors.Is(err, ErrTruncated))
+			assert.Equal(t, "Inventory", decodeErr.Path)
+		}
+	})
+	t.Run("should be ok without opened compound or list", func(t *testing.T) {
+		assert.NoError(t, NewTokenizer(NewBytesReader(nil, binary.BigEndian)).Skip())
+	})
+}
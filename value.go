@@ -0,0 +1,707 @@
+package gonbt
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// go struct fields can be mapped on nbt tags with the field's tag nbt like json:
+//
+//	type Player struct {
+//		Name      string  `nbt:"Name"`
+//		Health    float32 `nbt:"Health,omitempty"`
+//		Pos       []float64
+//		Inventory []Item  `nbt:"Inventory"`
+//		Internal  string  `nbt:"-"`
+//	}
+//
+// The name is optional (the go field name is used by default) and can be followed
+// by these options:
+//   - omitempty: the field is not written if it has the zero value
+//   - list: []byte, []int32 and []int64 are written as TAG_List rather than TAG_Byte_Array,
+//     TAG_Int_Array or TAG_Long_Array
+//
+// Fields of embedded structs without name are promoted like with encoding/json.
+// When several fields share the same name, the less nested one wins.
+//
+// Go types are converted like this:
+//   - bool, int8, uint8: TAG_Byte
+//   - int16, uint16: TAG_Short
+//   - int32, uint32: TAG_Int
+//   - int, int64, uint, uint64: TAG_Long
+//   - float32: TAG_Float
+//   - float64: TAG_Double
+//   - string: TAG_String
+//   - []byte, []int32, []int64: TAG_Byte_Array, TAG_Int_Array, TAG_Long_Array
+//   - other slices and arrays: TAG_List, typed with the go element type when they are empty
+//   - structs and maps with string keys: TAG_Compound
+//   - values implementing Tag are used as is
+//
+// On unmarshal, integer tags can be decoded in any go integer type that can hold the value
+// and a Tag can be assigned directly to a field of type Tag, interface{} or of the tag's own type.
+
+const (
+	optionOmitEmpty = "omitempty"
+	optionList      = "list"
+)
+
+var tagInterface = reflect.TypeOf((*Tag)(nil)).Elem()
+
+// UnmarshalInto data in the value pointed by v
+func UnmarshalInto(data []byte, v interface{}) error {
+	var err error
+	var t Tag
+
+	if t, err = Unmarshal(data); err != nil {
+		return err
+	}
+	return FromTag(t, v)
+}
+
+// MarshalValue v with the compression type
+func MarshalValue(v interface{}, compress string) ([]byte, error) {
+	var err error
+	var t Tag
+
+	if t, err = ToTag(v); err != nil {
+		return []byte{}, err
+	}
+	return Marshal(t, compress)
+}
+
+// FromTag set the value pointed by v with the tag content
+func FromTag(t Tag, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &TypeError{Op: opUnmarshal, Type: reflect.TypeOf(v), Msg: errorUnmarshalTarget}
+	}
+	d := &decodeState{}
+	return d.value(t, rv.Elem(), "", "")
+}
+
+// ToTag convert v to a Tag
+func ToTag(v interface{}) (Tag, error) {
+	var err error
+	var t Tag
+
+	e := &encodeState{}
+	if t, err = e.value(reflect.ValueOf(v), "", "", "", false); err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, &TypeError{Op: opMarshal, Type: reflect.TypeOf(v), Msg: errorNilValue}
+	}
+	return t, nil
+}
+
+// field describe a struct field mapped on a nbt tag
+type field struct {
+	name      string
+	goName    string
+	index     []int
+	depth     int
+	omitEmpty bool
+	asList    bool
+}
+
+var fieldCache sync.Map
+
+// cachedFields return the fields of the struct type t
+func cachedFields(t reflect.Type) []field {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.([]field)
+	}
+	f, _ := fieldCache.LoadOrStore(t, typeFields(t, nil, 0))
+	return f.([]field)
+}
+
+// typeFields list the fields of t with the embedded structs promoted
+func typeFields(t reflect.Type, index []int, depth int) []field {
+	var fields []field
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("nbt")
+		if tag == "-" {
+			continue
+		}
+		name, options := parseFieldTag(tag)
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		fieldIndex := append(append([]int{}, index...), i)
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct && !reflect.PtrTo(ft).Implements(tagInterface) {
+			fields = append(fields, typeFields(ft, fieldIndex, depth+1)...)
+			continue
+		}
+		if sf.PkgPath != "" {
+			// unexported field
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, field{
+			name:      name,
+			goName:    t.Name() + "." + sf.Name,
+			index:     fieldIndex,
+			depth:     depth,
+			omitEmpty: options[optionOmitEmpty],
+			asList:    options[optionList],
+		})
+	}
+	if depth > 0 {
+		return fields
+	}
+
+	// keep the less nested field for each name, the declaration order otherwise
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].depth < fields[j].depth })
+	dominants := fields[:0]
+	seen := make(map[string]bool)
+	for _, f := range fields {
+		if seen[f.name] {
+			continue
+		}
+		seen[f.name] = true
+		dominants = append(dominants, f)
+	}
+	sort.SliceStable(dominants, func(i, j int) bool { return lessIndex(dominants[i].index, dominants[j].index) })
+	return dominants
+}
+
+func lessIndex(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// parseFieldTag split the field's tag nbt in name and options
+func parseFieldTag(tag string) (string, map[string]bool) {
+	options := make(map[string]bool)
+
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		options[strings.TrimSpace(opt)] = true
+	}
+	return parts[0], options
+}
+
+// childPath return the nbt path of the child name
+func childPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// indexPath return the nbt path of the list element i
+func indexPath(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+// fieldByIndex return the struct field at index, allocating the embedded pointers if alloc is set.
+// The returned value is invalid when an embedded pointer is nil and alloc is not set, or when
+// it is a nil pointer to an unexported struct, which can't be allocated like in encoding/json
+func fieldByIndex(v reflect.Value, index []int, alloc bool) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// isEmptyValue report if v is the zero value to the omitempty option
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// intValue return the value of an integer tag and its size in bits
+func intValue(t Tag) (int64, int, bool) {
+	switch tag := t.(type) {
+	case *ByteT:
+		return int64(int8(tag.Value)), 8, true
+	case *ShortT:
+		return int64(tag.Value), 16, true
+	case *IntT:
+		return int64(tag.Value), 32, true
+	case *LongT:
+		return tag.Value, 64, true
+	}
+	return 0, 0, false
+}
+
+// listElems return the elements of a list or an array tag
+func listElems(t Tag) ([]interface{}, bool) {
+	var elems []interface{}
+
+	switch tag := t.(type) {
+	case *ListT:
+		return tag.Value, true
+	case *ByteArrayT:
+		for _, v := range tag.Value {
+			elems = append(elems, &ByteT{Value: v})
+		}
+	case *IntArrayT:
+		for _, v := range tag.Value {
+			elems = append(elems, &IntT{Value: v})
+		}
+	case *LongArrayT:
+		for _, v := range tag.Value {
+			elems = append(elems, &LongT{Value: v})
+		}
+	default:
+		return nil, false
+	}
+	return elems, true
+}
+
+// decodeState convert a Tag to a go value
+type decodeState struct{}
+
+func (d *decodeState) error(t Tag, v reflect.Value, path, goField, msg string) error {
+	tagT, _ := TagType(t)
+	return &TypeError{Op: opUnmarshal, Path: path, Field: goField, Type: v.Type(), Tag: tagT, Msg: msg}
+}
+
+func (d *decodeState) value(t Tag, v reflect.Value, path, goField string) error {
+	if t == nil {
+		return nil
+	}
+	tv := reflect.ValueOf(t)
+	if tv.Type().AssignableTo(v.Type()) {
+		v.Set(tv)
+		return nil
+	}
+	if tv.Kind() == reflect.Ptr && tv.Type().Elem() == v.Type() {
+		v.Set(tv.Elem())
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.value(t, v.Elem(), path, goField)
+	case reflect.Bool:
+		i, _, ok := intValue(t)
+		if !ok {
+			return d.error(t, v, path, goField, errorMismatchType)
+		}
+		v.SetBool(i != 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, _, ok := intValue(t)
+		if !ok {
+			return d.error(t, v, path, goField, errorMismatchType)
+		}
+		if v.OverflowInt(i) {
+			return d.error(t, v, path, goField, errorOverflow)
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i, bits, ok := intValue(t)
+		if !ok {
+			return d.error(t, v, path, goField, errorMismatchType)
+		}
+		// the unsigned values with the same size than the tag are stored with their bits
+		if bits == v.Type().Bits() {
+			v.SetUint(uint64(i) & (1<<uint(bits) - 1))
+			return nil
+		}
+		if i < 0 || v.OverflowUint(uint64(i)) {
+			return d.error(t, v, path, goField, errorOverflow)
+		}
+		v.SetUint(uint64(i))
+	case reflect.Float32, reflect.Float64:
+		switch tag := t.(type) {
+		case *FloatT:
+			v.SetFloat(float64(tag.Value))
+		case *DoubleT:
+			v.SetFloat(tag.Value)
+		default:
+			return d.error(t, v, path, goField, errorMismatchType)
+		}
+	case reflect.String:
+		tag, ok := t.(*StringT)
+		if !ok {
+			return d.error(t, v, path, goField, errorMismatchType)
+		}
+		v.SetString(tag.Value)
+	case reflect.Slice:
+		return d.slice(t, v, path, goField)
+	case reflect.Array:
+		return d.array(t, v, path, goField)
+	case reflect.Map:
+		return d.mapping(t, v, path, goField)
+	case reflect.Struct:
+		return d.object(t, v, path, goField)
+	default:
+		return d.error(t, v, path, goField, errorUnsupportedType)
+	}
+	return nil
+}
+
+func (d *decodeState) slice(t Tag, v reflect.Value, path, goField string) error {
+	var err error
+
+	switch tag := t.(type) {
+	case *ByteArrayT:
+		if v.Type() == reflect.TypeOf([]byte{}) {
+			v.SetBytes(append([]byte{}, tag.Value...))
+			return nil
+		}
+	case *IntArrayT:
+		if v.Type() == reflect.TypeOf([]int32{}) {
+			v.Set(reflect.ValueOf(append([]int32{}, tag.Value...)))
+			return nil
+		}
+	case *LongArrayT:
+		if v.Type() == reflect.TypeOf([]int64{}) {
+			v.Set(reflect.ValueOf(append([]int64{}, tag.Value...)))
+			return nil
+		}
+	}
+
+	elems, ok := listElems(t)
+	if !ok {
+		return d.error(t, v, path, goField, errorMismatchType)
+	}
+	s := reflect.MakeSlice(v.Type(), len(elems), len(elems))
+	for i, elem := range elems {
+		if _, ok := elem.(Tag); !ok {
+			return d.error(t, v, path, goField, errorTag)
+		}
+		if err = d.value(elem.(Tag), s.Index(i), indexPath(path, i), goField); err != nil {
+			return err
+		}
+	}
+	v.Set(s)
+	return nil
+}
+
+func (d *decodeState) array(t Tag, v reflect.Value, path, goField string) error {
+	var err error
+
+	elems, ok := listElems(t)
+	if !ok {
+		return d.error(t, v, path, goField, errorMismatchType)
+	}
+	if len(elems) > v.Len() {
+		return d.error(t, v, path, goField, errorListLength)
+	}
+	for i := 0; i < v.Len(); i++ {
+		if i >= len(elems) {
+			v.Index(i).Set(reflect.Zero(v.Type().Elem()))
+			continue
+		}
+		if _, ok := elems[i].(Tag); !ok {
+			return d.error(t, v, path, goField, errorTag)
+		}
+		if err = d.value(elems[i].(Tag), v.Index(i), indexPath(path, i), goField); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *decodeState) mapping(t Tag, v reflect.Value, path, goField string) error {
+	var err error
+
+	tag, ok := t.(*CompoundT)
+	if !ok {
+		return d.error(t, v, path, goField, errorMismatchType)
+	}
+	if v.Type().Key().Kind() != reflect.String {
+		return d.error(t, v, path, goField, errorMapKey)
+	}
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+	for key, value := range tag.Value {
+		if _, ok := value.(Tag); !ok {
+			return d.error(t, v, path, goField, errorTag)
+		}
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if err = d.value(value.(Tag), elem, childPath(path, key), goField); err != nil {
+			return err
+		}
+		v.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), elem)
+	}
+	return nil
+}
+
+func (d *decodeState) object(t Tag, v reflect.Value, path, goField string) error {
+	var err error
+
+	tag, ok := t.(*CompoundT)
+	if !ok {
+		return d.error(t, v, path, goField, errorMismatchType)
+	}
+	for _, f := range cachedFields(v.Type()) {
+		value, ok := tag.Value[f.name]
+		if !ok {
+			continue
+		}
+		if _, ok := value.(Tag); !ok {
+			return d.error(t, v, path, goField, errorTag)
+		}
+		fv := fieldByIndex(v, f.index, true)
+		if !fv.IsValid() {
+			return d.error(value.(Tag), v, childPath(path, f.name), f.goName, errorEmbeddedPointer)
+		}
+		if err = d.value(value.(Tag), fv, childPath(path, f.name), f.goName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeState convert a go value to a Tag
+type encodeState struct{}
+
+func (e *encodeState) error(v reflect.Value, path, goField, msg string) error {
+	return &TypeError{Op: opMarshal, Path: path, Field: goField, Type: v.Type(), Msg: msg}
+}
+
+// value return the tag of v or nil if v is a nil pointer or interface
+func (e *encodeState) value(v reflect.Value, name, path, goField string, asList bool) (Tag, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if v.Type().Implements(tagInterface) {
+		if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+			return nil, nil
+		}
+		return v.Interface().(Tag), nil
+	}
+	if reflect.PtrTo(v.Type()).Implements(tagInterface) {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		return p.Interface().(Tag), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return e.value(v.Elem(), name, path, goField, asList)
+	case reflect.Bool:
+		var b byte
+		if v.Bool() {
+			b = 1
+		}
+		return &ByteT{Name: name, Value: b}, nil
+	case reflect.Int8:
+		return &ByteT{Name: name, Value: byte(v.Int())}, nil
+	case reflect.Uint8:
+		return &ByteT{Name: name, Value: byte(v.Uint())}, nil
+	case reflect.Int16:
+		return &ShortT{Name: name, Value: int16(v.Int())}, nil
+	case reflect.Uint16:
+		return &ShortT{Name: name, Value: int16(v.Uint())}, nil
+	case reflect.Int32:
+		return &IntT{Name: name, Value: int32(v.Int())}, nil
+	case reflect.Uint32:
+		return &IntT{Name: name, Value: int32(v.Uint())}, nil
+	case reflect.Int, reflect.Int64:
+		return &LongT{Name: name, Value: v.Int()}, nil
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return &LongT{Name: name, Value: int64(v.Uint())}, nil
+	case reflect.Float32:
+		return &FloatT{Name: name, Value: float32(v.Float())}, nil
+	case reflect.Float64:
+		return &DoubleT{Name: name, Value: v.Float()}, nil
+	case reflect.String:
+		return &StringT{Name: name, Value: v.String()}, nil
+	case reflect.Slice, reflect.Array:
+		return e.list(v, name, path, goField, asList)
+	case reflect.Map:
+		return e.mapping(v, name, path, goField)
+	case reflect.Struct:
+		return e.object(v, name, path)
+	default:
+		return nil, e.error(v, path, goField, errorUnsupportedType)
+	}
+}
+
+func (e *encodeState) list(v reflect.Value, name, path, goField string, asList bool) (Tag, error) {
+	var err error
+
+	if !asList {
+		switch v.Type().Elem().Kind() {
+		case reflect.Int8, reflect.Uint8:
+			value := make([]byte, v.Len())
+			for i := range value {
+				if v.Index(i).Kind() == reflect.Int8 {
+					value[i] = byte(v.Index(i).Int())
+				} else {
+					value[i] = byte(v.Index(i).Uint())
+				}
+			}
+			return &ByteArrayT{Name: name, Value: value}, nil
+		case reflect.Int32:
+			value := make([]int32, v.Len())
+			for i := range value {
+				value[i] = int32(v.Index(i).Int())
+			}
+			return &IntArrayT{Name: name, Value: value}, nil
+		case reflect.Int64:
+			value := make([]int64, v.Len())
+			for i := range value {
+				value[i] = v.Index(i).Int()
+			}
+			return &LongArrayT{Name: name, Value: value}, nil
+		}
+	}
+
+	list := &ListT{Name: name, ElemType: elemTagType(v.Type().Elem()), Value: []interface{}{}}
+	var listT byte
+	for i := 0; i < v.Len(); i++ {
+		var elem Tag
+		var tagT byte
+
+		elemPath := indexPath(path, i)
+		if elem, err = e.value(v.Index(i), "", elemPath, goField, false); err != nil {
+			return nil, err
+		}
+		if elem == nil {
+			return nil, e.error(v.Index(i), elemPath, goField, errorNilValue)
+		}
+		if tagT, err = TagType(elem); err != nil {
+			return nil, e.error(v.Index(i), elemPath, goField, errorTag)
+		}
+		if i == 0 {
+			listT = tagT
+			list.ElemType = tagT
+		} else if tagT != listT {
+			return nil, e.error(v.Index(i), elemPath, goField, errorListType)
+		}
+		list.Value = append(list.Value, elem)
+	}
+	return list, nil
+}
+
+// elemTagType return the tag type of the go type t, to type the empty lists.
+// It return TagEnd when the type is only known from the values, like interface{}
+func elemTagType(t reflect.Type) byte {
+	if t.Implements(tagInterface) {
+		if t.Kind() != reflect.Ptr {
+			return TagEnd
+		}
+		tagT, _ := TagType(reflect.New(t.Elem()).Interface().(Tag))
+		return tagT
+	}
+	if reflect.PtrTo(t).Implements(tagInterface) {
+		tagT, _ := TagType(reflect.New(t).Interface().(Tag))
+		return tagT
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return elemTagType(t.Elem())
+	case reflect.Bool, reflect.Int8, reflect.Uint8:
+		return TagByte
+	case reflect.Int16, reflect.Uint16:
+		return TagShort
+	case reflect.Int32, reflect.Uint32:
+		return TagInt
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return TagLong
+	case reflect.Float32:
+		return TagFloat
+	case reflect.Float64:
+		return TagDouble
+	case reflect.String:
+		return TagString
+	case reflect.Slice, reflect.Array:
+		switch t.Elem().Kind() {
+		case reflect.Int8, reflect.Uint8:
+			return TagByteArray
+		case reflect.Int32:
+			return TagIntArray
+		case reflect.Int64:
+			return TagLongArray
+		}
+		return TagList
+	case reflect.Map, reflect.Struct:
+		return TagCompound
+	}
+	return TagEnd
+}
+
+func (e *encodeState) mapping(v reflect.Value, name, path, goField string) (Tag, error) {
+	var err error
+
+	if v.Type().Key().Kind() != reflect.String {
+		return nil, e.error(v, path, goField, errorMapKey)
+	}
+	compound := &CompoundT{Name: name, Value: make(map[string]interface{})}
+	iter := v.MapRange()
+	for iter.Next() {
+		var elem Tag
+
+		key := iter.Key().String()
+		if elem, err = e.value(iter.Value(), key, childPath(path, key), goField, false); err != nil {
+			return nil, err
+		}
+		if elem == nil {
+			continue
+		}
+		compound.Value[key] = elem
+	}
+	return compound, nil
+}
+
+func (e *encodeState) object(v reflect.Value, name, path string) (Tag, error) {
+	var err error
+
+	compound := &CompoundT{Name: name, Value: make(map[string]interface{})}
+	for _, f := range cachedFields(v.Type()) {
+		var elem Tag
+
+		fv := fieldByIndex(v, f.index, false)
+		if !fv.IsValid() || (f.omitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+		if elem, err = e.value(fv, f.name, childPath(path, f.name), f.goName, f.asList); err != nil {
+			return nil, err
+		}
+		if elem == nil {
+			continue
+		}
+		compound.Value[f.name] = elem
+		compound.Order = append(compound.Order, f.name)
+	}
+	return compound, nil
+}
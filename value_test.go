@@ -0,0 +1,259 @@
+package gonbt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testItem struct {
+	Slot  int8   `nbt:"Slot"`
+	ID    string `nbt:"id"`
+	Count byte   `nbt:"Count"`
+}
+
+type testEntity struct {
+	UUID []int32 `nbt:"UUID"`
+}
+
+type testPlayer struct {
+	testEntity
+	Name      string     `nbt:"Name"`
+	Health    float32    `nbt:"Health,omitempty"`
+	Pos       []float64  `nbt:"Pos"`
+	OnGround  bool       `nbt:"OnGround"`
+	XP        uint16     `nbt:"XpLevel"`
+	Seed      int64      `nbt:"Seed"`
+	Heights   []int32    `nbt:"Heights,list"`
+	Inventory []testItem `nbt:"Inventory"`
+	Extra     Tag        `nbt:"Extra,omitempty"`
+	Ignored   string     `nbt:"-"`
+	Motion    *[3]float64
+	internal  int
+}
+
+type testInner struct {
+	X int32
+}
+
+type testOuter struct {
+	*testInner
+	Y int32
+}
+
+func TestToTag(t *testing.T) {
+	t.Run("should return an error because the value is nil", func(t *testing.T) {
+		tag, err := ToTag(nil)
+		if assert.Error(t, err) {
+			assert.Nil(t, tag)
+		}
+	})
+	t.Run("should return an error because the go type is not supported", func(t *testing.T) {
+		_, err := ToTag(map[string]interface{}{"Chan": make(chan int)})
+		var typeErr *TypeError
+		if assert.True(t, errors.As(err, &typeErr)) {
+			assert.EqualValues(t, "Chan", typeErr.Path)
+			assert.EqualValues(t, errorUnsupportedType, typeErr.Msg)
+		}
+	})
+	t.Run("should return an error because the map key is not a string", func(t *testing.T) {
+		_, err := ToTag(map[int]string{1: "one"})
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), errorMapKey)
+		}
+	})
+	t.Run("should return an error because the list elements are mixed", func(t *testing.T) {
+		_, err := ToTag(struct{ List []interface{} }{List: []interface{}{int32(1), "two"}})
+		var typeErr *TypeError
+		if assert.True(t, errors.As(err, &typeErr)) {
+			assert.EqualValues(t, "List[1]", typeErr.Path)
+			assert.EqualValues(t, errorListType, typeErr.Msg)
+		}
+	})
+	t.Run("should be ok with a struct", func(t *testing.T) {
+		player := testPlayer{
+			testEntity: testEntity{UUID: []int32{1, 2, 3, 4}},
+			Name:       "Steve",
+			Pos:        []float64{1.5, 64, -3},
+			OnGround:   true,
+			XP:         65535,
+			Seed:       42,
+			Heights:    []int32{7},
+			Inventory:  []testItem{{Slot: -106, ID: "minecraft:stone", Count: 64}},
+			Ignored:    "ignored",
+		}
+		expectedTag := &CompoundT{Value: map[string]interface{}{
+			"UUID":     &IntArrayT{Name: "UUID", Value: []int32{1, 2, 3, 4}},
+			"Name":     &StringT{Name: "Name", Value: "Steve"},
+			"Pos":      &ListT{Name: "Pos", ElemType: TagDouble, Value: []interface{}{&DoubleT{Value: 1.5}, &DoubleT{Value: 64}, &DoubleT{Value: -3}}},
+			"OnGround": &ByteT{Name: "OnGround", Value: 1},
+			"XpLevel":  &ShortT{Name: "XpLevel", Value: -1},
+			"Seed":     &LongT{Name: "Seed", Value: 42},
+			"Heights":  &ListT{Name: "Heights", ElemType: TagInt, Value: []interface{}{&IntT{Value: 7}}},
+			"Inventory": &ListT{Name: "Inventory", ElemType: TagCompound, Value: []interface{}{
+				&CompoundT{Value: map[string]interface{}{
+					"Slot":  &ByteT{Name: "Slot", Value: 0x96},
+					"id":    &StringT{Name: "id", Value: "minecraft:stone"},
+					"Count": &ByteT{Name: "Count", Value: 64},
+				}, Order: []string{"Slot", "id", "Count"}},
+			}},
+		}, Order: []string{"UUID", "Name", "Pos", "OnGround", "XpLevel", "Seed", "Heights", "Inventory"}}
+
+		tag, err := ToTag(player)
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, expectedTag, tag)
+		}
+	})
+	t.Run("should be ok with the element type of the empty and nested lists", func(t *testing.T) {
+		v := struct {
+			Inventory []testItem
+			Lore      [][]string
+			Maps      [][]int32
+			Tags      []*IntT
+			Any       []interface{}
+		}{Lore: [][]string{{"a"}, {}}}
+
+		tag, err := ToTag(v)
+		if assert.NoError(t, err) {
+			compound := tag.(*CompoundT)
+			assert.EqualValues(t, TagCompound, compound.Value["Inventory"].(*ListT).ElemType)
+			lore := compound.Value["Lore"].(*ListT)
+			assert.EqualValues(t, TagList, lore.ElemType)
+			assert.EqualValues(t, TagString, lore.Value[0].(*ListT).ElemType)
+			assert.EqualValues(t, TagString, lore.Value[1].(*ListT).ElemType)
+			assert.EqualValues(t, TagIntArray, compound.Value["Maps"].(*ListT).ElemType)
+			assert.EqualValues(t, TagInt, compound.Value["Tags"].(*ListT).ElemType)
+			assert.EqualValues(t, TagEnd, compound.Value["Any"].(*ListT).ElemType)
+		}
+	})
+	t.Run("should be ok with a binary round trip of an empty list", func(t *testing.T) {
+		data, err := MarshalValue(testPlayer{}, CompressNone)
+		if assert.NoError(t, err) {
+			tag, err := Unmarshal(data)
+			if assert.NoError(t, err) {
+				inventory := tag.(*CompoundT).Value["Inventory"].(*ListT)
+				assert.Empty(t, inventory.Value)
+				assert.EqualValues(t, TagCompound, inventory.ElemType)
+			}
+		}
+	})
+}
+
+func TestFromTag(t *testing.T) {
+	t.Run("should return an error because the target is not a pointer", func(t *testing.T) {
+		var player testPlayer
+
+		err := FromTag(&CompoundT{}, player)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), errorUnmarshalTarget)
+		}
+	})
+	t.Run("should return an error with the field and the path", func(t *testing.T) {
+		var player testPlayer
+		tag := &CompoundT{Value: map[string]interface{}{
+			"Inventory": &ListT{Value: []interface{}{
+				&CompoundT{Value: map[string]interface{}{"id": &IntT{Value: 1}}},
+			}},
+		}}
+
+		err := FromTag(tag, &player)
+		var typeErr *TypeError
+		if assert.True(t, errors.As(err, &typeErr)) {
+			assert.EqualValues(t, "Inventory[0].id", typeErr.Path)
+			assert.EqualValues(t, "testItem.ID", typeErr.Field)
+			assert.EqualValues(t, TagInt, typeErr.Tag)
+			assert.EqualValues(t, "unmarshal TAG_Int into go value of type string (field testItem.ID) at Inventory[0].id: tag type mismatch", err.Error())
+		}
+	})
+	t.Run("should return an error because the value overflows the field", func(t *testing.T) {
+		var v struct{ Count int8 }
+
+		err := FromTag(&CompoundT{Value: map[string]interface{}{"Count": &IntT{Value: 300}}}, &v)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), errorOverflow)
+		}
+	})
+	t.Run("should be ok with a round trip", func(t *testing.T) {
+		var player testPlayer
+		motion := [3]float64{0.5, 0, -0.5}
+		expected := testPlayer{
+			testEntity: testEntity{UUID: []int32{1, 2, 3, 4}},
+			Name:       "Alex",
+			Health:     20,
+			Pos:        []float64{1.5, 64, -3},
+			XP:         65535,
+			Seed:       -42,
+			Heights:    []int32{7, 8},
+			Inventory:  []testItem{{Slot: -106, ID: "minecraft:stone", Count: 64}, {Slot: 1, ID: "minecraft:dirt", Count: 1}},
+			Extra:      &StringT{Name: "Extra", Value: "raw tag"},
+			Motion:     &motion,
+		}
+
+		tag, err := ToTag(expected)
+		if assert.NoError(t, err) {
+			err = FromTag(tag, &player)
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, expected, player)
+			}
+		}
+	})
+	t.Run("should be ok with a binary round trip", func(t *testing.T) {
+		var item testItem
+		expected := testItem{Slot: 3, ID: "minecraft:dirt", Count: 12}
+
+		data, err := MarshalValue(expected, CompressNone)
+		if assert.NoError(t, err) {
+			err = UnmarshalInto(data, &item)
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, expected, item)
+			}
+		}
+	})
+	t.Run("should return an error because the embedded pointer to an unexported struct is nil", func(t *testing.T) {
+		var outer testOuter
+
+		data, err := MarshalValue(testOuter{testInner: &testInner{X: 1}, Y: 2}, CompressNone)
+		if assert.NoError(t, err) {
+			err = UnmarshalInto(data, &outer)
+			var typeErr *TypeError
+			if assert.True(t, errors.As(err, &typeErr)) {
+				assert.EqualValues(t, "X", typeErr.Path)
+				assert.EqualValues(t, "testInner.X", typeErr.Field)
+				assert.EqualValues(t, errorEmbeddedPointer, typeErr.Msg)
+			}
+		}
+	})
+	t.Run("should be ok with an allocated embedded pointer to an unexported struct", func(t *testing.T) {
+		outer := testOuter{testInner: &testInner{}}
+
+		data, err := MarshalValue(testOuter{testInner: &testInner{X: 1}, Y: 2}, CompressNone)
+		if assert.NoError(t, err) {
+			err = UnmarshalInto(data, &outer)
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, testOuter{testInner: &testInner{X: 1}, Y: 2}, outer)
+			}
+		}
+	})
+	t.Run("should be ok with a map and the integer conversions", func(t *testing.T) {
+		var v map[string]int
+		tag := &CompoundT{Value: map[string]interface{}{
+			"a": &ByteT{Value: 0xff},
+			"b": &ShortT{Value: 300},
+			"c": &LongT{Value: 1 << 40},
+		}}
+
+		err := FromTag(tag, &v)
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, map[string]int{"a": -1, "b": 300, "c": 1 << 40}, v)
+		}
+	})
+	t.Run("should be ok with an array tag in a slice of int", func(t *testing.T) {
+		var v []int
+
+		err := FromTag(&IntArrayT{Value: []int32{1, -2}}, &v)
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, []int{1, -2}, v)
+		}
+	})
+}
@@ -0,0 +1,364 @@
+package gonbt
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// maximum size in bytes of the 32 and 64 bits varints
+const (
+	maxVarintLen32 = 5
+	maxVarintLen64 = 10
+)
+
+// maxVarintString is the maximum length of the strings of the bedrock network protocol
+const maxVarintString = math.MaxInt16
+
+// varintReader read the nbt of the bedrock network protocol: the Int, Long and the
+// lengths of the lists and arrays are zigzag varints, the string lengths are unsigned
+// varints and the other values are little-endian
+type varintReader struct {
+	flux io.Reader
+	buf  [8]byte
+	limiter
+}
+
+// NewVarintReader nbt with the varint encoding of the bedrock network protocol
+func NewVarintReader(r io.Reader) Reader {
+	return &varintReader{
+		flux:    r,
+		limiter: limiter{limits: DefaultLimits()},
+	}
+}
+
+// read fill the n first bytes of the scratch buffer
+func (r *varintReader) read(n int) ([]byte, error) {
+	var err error
+
+	if _, err = io.ReadFull(r.flux, r.buf[:n]); err != nil {
+		return nil, truncated(err)
+	}
+	return r.buf[:n], nil
+}
+
+// uvarint read an unsigned varint of maxLen bytes at most
+func (r *varintReader) uvarint(maxLen int) (uint64, error) {
+	var x uint64
+	var err error
+	var b []byte
+
+	for i := 0; i < maxLen; i++ {
+		if b, err = r.read(1); err != nil {
+			return 0, err
+		}
+		x |= uint64(b[0]&0x7f) << (7 * uint(i))
+		if b[0] < 0x80 {
+			return x, nil
+		}
+	}
+	return 0, errors.New(errorVarint)
+}
+
+// varint32 read a zigzag varint of 32 bits
+func (r *varintReader) varint32() (int32, error) {
+	var ux uint64
+	var err error
+
+	if ux, err = r.uvarint(maxVarintLen32); err != nil {
+		return 0, err
+	}
+	if ux > math.MaxUint32 {
+		return 0, errors.New(errorVarint)
+	}
+	return int32(uint32(ux)>>1) ^ -int32(ux&1), nil
+}
+
+// varint64 read a zigzag varint of 64 bits
+func (r *varintReader) varint64() (int64, error) {
+	var ux uint64
+	var err error
+
+	if ux, err = r.uvarint(maxVarintLen64); err != nil {
+		return 0, err
+	}
+	return int64(ux>>1) ^ -int64(ux&1), nil
+}
+
+// arrayLength read the zigzag varint length of an array
+func (r *varintReader) arrayLength() (int32, error) {
+	var size int32
+	var err error
+
+	if size, err = r.varint32(); err != nil {
+		return 0, err
+	}
+	if err = r.length(size); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// String reader with the unsigned varint length
+func (r *varintReader) String() (string, error) {
+	var size uint64
+	var data []byte
+	var err error
+
+	if size, err = r.uvarint(maxVarintLen32); err != nil {
+		return "", err
+	}
+	if size > maxVarintString {
+		return "", errors.New(errorStringLength)
+	}
+	if data, err = readFull(r.flux, int64(size)); err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Byte reader, it return io.EOF at the end of data
+func (r *varintReader) Byte() (byte, error) {
+	var err error
+
+	if _, err = io.ReadFull(r.flux, r.buf[:1]); err != nil {
+		return 0, err
+	}
+	return r.buf[0], nil
+}
+
+// Short reader little-endian
+func (r *varintReader) Short() (int16, error) {
+	var b []byte
+	var err error
+
+	if b, err = r.read(2); err != nil {
+		return 0, err
+	}
+	return int16(binary.LittleEndian.Uint16(b)), nil
+}
+
+// Int reader with zigzag varint
+func (r *varintReader) Int() (int32, error) {
+	return r.varint32()
+}
+
+// Long reader with zigzag varint
+func (r *varintReader) Long() (int64, error) {
+	return r.varint64()
+}
+
+// Float reader little-endian
+func (r *varintReader) Float() (float32, error) {
+	var b []byte
+	var err error
+
+	if b, err = r.read(4); err != nil {
+		return 0, err
+	}
+	return math.Float32frombits(binary.LittleEndian.Uint32(b)), nil
+}
+
+// Double reader little-endian
+func (r *varintReader) Double() (float64, error) {
+	var b []byte
+	var err error
+
+	if b, err = r.read(8); err != nil {
+		return 0, err
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+}
+
+// Bytes reader with the zigzag varint length
+func (r *varintReader) Bytes() ([]byte, error) {
+	var size int32
+	var data []byte
+	var err error
+
+	if size, err = r.arrayLength(); err != nil {
+		return []byte{}, err
+	}
+	if data, err = readFull(r.flux, int64(size)); err != nil {
+		return []byte{}, err
+	}
+	return data, nil
+}
+
+// IntArray reader with the zigzag varint length and elements
+func (r *varintReader) IntArray() ([]int32, error) {
+	var size int32
+	var err error
+
+	if size, err = r.arrayLength(); err != nil {
+		return []int32{}, err
+	}
+	ret := make([]int32, 0, minCap(size))
+	for i := int32(0); i < size; i++ {
+		var elem int32
+		if elem, err = r.varint32(); err != nil {
+			return []int32{}, err
+		}
+		ret = append(ret, elem)
+	}
+	return ret, nil
+}
+
+// LongArray reader with the zigzag varint length and elements
+func (r *varintReader) LongArray() ([]int64, error) {
+	var size int32
+	var err error
+
+	if size, err = r.arrayLength(); err != nil {
+		return []int64{}, err
+	}
+	ret := make([]int64, 0, minCap(size))
+	for i := int32(0); i < size; i++ {
+		var elem int64
+		if elem, err = r.varint64(); err != nil {
+			return []int64{}, err
+		}
+		ret = append(ret, elem)
+	}
+	return ret, nil
+}
+
+// minCap bound the preallocated capacity, the length comes from the data
+func minCap(size int32) int32 {
+	if size > 1024 {
+		return 1024
+	}
+	return size
+}
+
+// varintWriter write the nbt of the bedrock network protocol
+type varintWriter struct {
+	flux io.Writer
+	buf  [maxVarintLen64]byte
+}
+
+// NewVarintWriter nbt with the varint encoding of the bedrock network protocol
+func NewVarintWriter(driver io.Writer) Writer {
+	return &varintWriter{
+		flux: driver,
+	}
+}
+
+// write the n first bytes of the scratch buffer
+func (w *varintWriter) write(n int) error {
+	var err error
+
+	if _, err = w.flux.Write(w.buf[:n]); err != nil {
+		return err
+	}
+	return nil
+}
+
+// uvarint write an unsigned varint
+func (w *varintWriter) uvarint(v uint64) error {
+	return w.write(binary.PutUvarint(w.buf[:], v))
+}
+
+// varint32 write a zigzag varint of 32 bits
+func (w *varintWriter) varint32(v int32) error {
+	return w.uvarint(uint64(uint32(v<<1) ^ uint32(v>>31)))
+}
+
+// varint64 write a zigzag varint of 64 bits
+func (w *varintWriter) varint64(v int64) error {
+	return w.uvarint(uint64(v<<1) ^ uint64(v>>63))
+}
+
+// String write with the unsigned varint length
+func (w *varintWriter) String(str string) error {
+	var err error
+
+	if len(str) > maxVarintString {
+		return errors.New(errorStringLength)
+	}
+	if err = w.uvarint(uint64(len(str))); err != nil {
+		return err
+	}
+	if _, err = io.WriteString(w.flux, str); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Byte write
+func (w *varintWriter) Byte(b byte) error {
+	w.buf[0] = b
+	return w.write(1)
+}
+
+// Short write little-endian
+func (w *varintWriter) Short(v int16) error {
+	binary.LittleEndian.PutUint16(w.buf[:], uint16(v))
+	return w.write(2)
+}
+
+// Int write with zigzag varint
+func (w *varintWriter) Int(v int32) error {
+	return w.varint32(v)
+}
+
+// Long write with zigzag varint
+func (w *varintWriter) Long(v int64) error {
+	return w.varint64(v)
+}
+
+// Float write little-endian
+func (w *varintWriter) Float(v float32) error {
+	binary.LittleEndian.PutUint32(w.buf[:], math.Float32bits(v))
+	return w.write(4)
+}
+
+// Double write little-endian
+func (w *varintWriter) Double(v float64) error {
+	binary.LittleEndian.PutUint64(w.buf[:], math.Float64bits(v))
+	return w.write(8)
+}
+
+// Bytes write with the zigzag varint length
+func (w *varintWriter) Bytes(v []byte) error {
+	var err error
+
+	if err = w.varint32(int32(len(v))); err != nil {
+		return err
+	}
+	if _, err = w.flux.Write(v); err != nil {
+		return err
+	}
+	return nil
+}
+
+// IntArray write with the zigzag varint length and elements
+func (w *varintWriter) IntArray(values []int32) error {
+	var err error
+
+	if err = w.varint32(int32(len(values))); err != nil {
+		return err
+	}
+	for _, v := range values {
+		if err = w.varint32(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LongArray write with the zigzag varint length and elements
+func (w *varintWriter) LongArray(values []int64) error {
+	var err error
+
+	if err = w.varint32(int32(len(values))); err != nil {
+		return err
+	}
+	for _, v := range values {
+		if err = w.varint64(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package gonbt
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVarintReader(t *testing.T) {
+	t.Run("should return io.EOF because the reader is empty", func(t *testing.T) {
+		_, err := NewVarintReader(bytes.NewReader([]byte{})).Byte()
+		assert.Equal(t, io.EOF, err)
+		_, err = NewVarintReader(bytes.NewReader([]byte{})).Int()
+		assert.Equal(t, ErrTruncated, err)
+	})
+	t.Run("should return an error because the varint is truncated", func(t *testing.T) {
+		_, err := NewVarintReader(bytes.NewReader([]byte{0x80})).Int()
+		assert.Equal(t, ErrTruncated, err)
+	})
+	t.Run("should return an error because the varint overflows", func(t *testing.T) {
+		_, err := NewVarintReader(bytes.NewReader([]byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x01})).Int()
+		if assert.Error(t, err) {
+			assert.EqualValues(t, errorVarint, err.Error())
+		}
+		_, err = NewVarintReader(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 0x7f})).Int()
+		if assert.Error(t, err) {
+			assert.EqualValues(t, errorVarint, err.Error())
+		}
+	})
+	t.Run("should return an error because the string is too long", func(t *testing.T) {
+		_, err := NewVarintReader(bytes.NewReader([]byte{0x80, 0x80, 0x02})).String()
+		if assert.Error(t, err) {
+			assert.EqualValues(t, errorStringLength, err.Error())
+		}
+	})
+	t.Run("should return an error because the length is negative", func(t *testing.T) {
+		_, err := NewVarintReader(bytes.NewReader([]byte{0x01})).Bytes()
+		if assert.Error(t, err) {
+			assert.EqualValues(t, errorNegativeLength, err.Error())
+		}
+	})
+	t.Run("should be ok with the zigzag varints", func(t *testing.T) {
+		r := NewVarintReader(bytes.NewReader([]byte{
+			0x00, 0x01, 0x02, 0xfe, 0xff, 0xff, 0xff, 0x0f, 0xff, 0xff, 0xff, 0xff, 0x0f,
+			0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01,
+		}))
+		for _, expected := range []int32{0, -1, 1, math.MaxInt32, math.MinInt32} {
+			v, err := r.Int()
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, expected, v)
+			}
+		}
+		v, err := r.Long()
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, int64(math.MinInt64), v)
+		}
+	})
+	t.Run("should be ok with the string and the arrays", func(t *testing.T) {
+		r := NewVarintReader(bytes.NewReader([]byte{
+			0x02, 'i', 'd',
+			0x04, 0x01, 0xff,
+			0x04, 0x01, 0x80, 0x01,
+			0x02, 0x03,
+			0x34, 0x12,
+			0x00, 0x00, 0xc0, 0x3f,
+		}))
+		str, err := r.String()
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, "id", str)
+		}
+		b, err := r.Bytes()
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, []byte{0x01, 0xff}, b)
+		}
+		ints, err := r.IntArray()
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, []int32{-1, 64}, ints)
+		}
+		longs, err := r.LongArray()
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, []int64{-2}, longs)
+		}
+		short, err := r.Short()
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, 0x1234, short)
+		}
+		f, err := r.Float()
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, float32(1.5), f)
+		}
+	})
+}
+
+func TestVarintWriter(t *testing.T) {
+	t.Run("should return an error because the string is too long", func(t *testing.T) {
+		w := NewVarintWriter(&bytes.Buffer{})
+
+		err := w.String(strings.Repeat("a", maxVarintString+1))
+		if assert.Error(t, err) {
+			assert.EqualValues(t, errorStringLength, err.Error())
+		}
+		assert.NoError(t, w.String(strings.Repeat("a", maxVarintString)))
+	})
+	t.Run("should be ok with the item of a packet", func(t *testing.T) {
+		var buf bytes.Buffer
+		tag := &CompoundT{}
+		tag.Set("Count", &ByteT{Value: 1})
+		tag.Set("Damage", &IntT{Value: -3})
+		tag.Set("Name", &StringT{Value: "stone"})
+		tag.Set("Lore", &ListT{Value: []interface{}{&StringT{Value: "a"}}})
+		expectedData := []byte{
+			TagCompound, 0x00,
+			TagByte, 0x05, 'C', 'o', 'u', 'n', 't', 0x01,
+			TagInt, 0x06, 'D', 'a', 'm', 'a', 'g', 'e', 0x05,
+			TagString, 0x04, 'N', 'a', 'm', 'e', 0x05, 's', 't', 'o', 'n', 'e',
+			TagList, 0x04, 'L', 'o', 'r', 'e', TagString, 0x02, 0x01, 'a',
+			TagEnd,
+		}
+
+		if assert.NoError(t, tag.Write(NewVarintWriter(&buf), true)) {
+			assert.EqualValues(t, expectedData, buf.Bytes())
+		}
+	})
+	t.Run("should be ok with a round trip through the stream", func(t *testing.T) {
+		var buf bytes.Buffer
+		tag := newTestTag()
+		tag.Set("Long", &LongT{Value: math.MinInt64})
+		tag.Set("Ints", &IntArrayT{Value: []int32{math.MaxInt32, -1}})
+		tag.Set("Double", &DoubleT{Value: -0.25})
+
+		encoder := NewEncoder(&buf)
+		assert.NoError(t, encoder.SetVarint(true))
+		if assert.NoError(t, encoder.Encode(tag)) {
+			decoder := NewDecoder(&buf)
+			assert.NoError(t, decoder.SetVarint(true))
+			decoded, err := decoder.Decode()
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, tag, decoded)
+			}
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package gonbt
+
+// WalkFunc is called by Walk for each tag with its path. It can return
+// SkipChildren to skip the elements of the tag, SkipAll to stop the walk, or
+// any other error which stops the walk and is returned by Walk
+type WalkFunc func(path Path, t Tag) error
+
+// Walk call fn for t and its nested tags in depth-first order, the elements
+// of the compounds are visited in the order of Keys
+func Walk(t Tag, fn WalkFunc) error {
+	if err := walk(Path{}, t, fn); err != SkipAll {
+		return err
+	}
+	return nil
+}
+
+func walk(path Path, t Tag, fn WalkFunc) error {
+	var err error
+
+	if err = fn(path, t); err == SkipChildren {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	switch tag := t.(type) {
+	case *CompoundT:
+		for _, name := range tag.Keys() {
+			if elem, ok := tag.Value[name].(Tag); ok {
+				if err = walk(path.child(name), elem, fn); err != nil {
+					return err
+				}
+			}
+		}
+	case *ListT:
+		for i := 0; i < len(tag.Value); i++ {
+			if elem, ok := tag.Value[i].(Tag); ok {
+				if err = walk(path.index(i), elem, fn); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// TransformFunc is called by Transform for each tag with its path. It return the
+// tag which replace t, t itself to keep it, or nil to delete it. The errors are
+// handled like with WalkFunc, the returned tag is used with SkipChildren and SkipAll
+// only, t is kept on any other error
+type TransformFunc func(path Path, t Tag) (Tag, error)
+
+// Transform call fn for t and its nested tags in depth-first order, then for the
+// elements of the tag returned by fn. The compounds and the lists are modified in
+// place, Transform return the new root, nil if it's deleted. The elements of a list
+// must keep the same tag type, otherwise ErrListType is returned. On error the root
+// is returned with the error, the tags may be partially transformed
+func Transform(t Tag, fn TransformFunc) (Tag, error) {
+	var err error
+
+	if t, err = transform(Path{}, t, fn); err != nil && err != SkipAll {
+		return t, err
+	}
+	return t, nil
+}
+
+func transform(path Path, t Tag, fn TransformFunc) (Tag, error) {
+	var ret Tag
+	var err error
+
+	// the original tag is kept on a real error
+	if ret, err = fn(path, t); err == SkipChildren {
+		return ret, nil
+	} else if err == SkipAll {
+		return ret, err
+	} else if err != nil {
+		return t, err
+	}
+	t = ret
+	switch tag := t.(type) {
+	case *CompoundT:
+		for _, name := range tag.Keys() {
+			var elem Tag
+
+			if elem, _ = tag.Value[name].(Tag); elem == nil {
+				continue
+			}
+			elem, err = transform(path.child(name), elem, fn)
+			if elem == nil {
+				tag.Delete(name)
+			} else {
+				tag.Set(name, elem)
+			}
+			if err != nil {
+				return t, err
+			}
+		}
+	case *ListT:
+		var tagT byte
+
+		if len(tag.Value) == 0 {
+			return t, nil
+		}
+		value := make([]interface{}, 0, len(tag.Value))
+		for i, v := range tag.Value {
+			elem, ok := v.(Tag)
+			if !ok {
+				value = append(value, v)
+				continue
+			}
+			if err == nil {
+				elem, err = transform(path.index(i), elem, fn)
+			}
+			if elem == nil {
+				continue
+			}
+			if elemT, typeErr := TagType(elem); typeErr != nil {
+				return t, typeErr
+			} else if len(value) == 0 {
+				tagT = elemT
+			} else if elemT != tagT {
+				return t, ErrListType
+			}
+			setName(elem, "")
+			value = append(value, elem)
+		}
+		tag.Value = value
+		if len(value) > 0 {
+			tag.ElemType = tagT
+		}
+		if err != nil {
+			return t, err
+		}
+	}
+	return t, nil
+}
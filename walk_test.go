@@ -0,0 +1,208 @@
+package gonbt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalk(t *testing.T) {
+	t.Run("should be ok with all the tags in depth-first order", func(t *testing.T) {
+		var paths []string
+
+		err := Walk(newPathTag(), func(path Path, t Tag) error {
+			paths = append(paths, path.String())
+			return nil
+		})
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"", "Pos", "Pos[0]", "Pos[1]", "Pos[2]", `"dotted.name"`, "Seeds", "Inventory",
+				"Inventory[0]", "Inventory[0].Slot", "Inventory[0].id", "Inventory[0].Count", "Inventory[0].tag",
+				"Inventory[0].tag.display", "Inventory[0].tag.display.Name", "Inventory[0].tag.Lore",
+				"Inventory[0].tag.Lore[0]", "Inventory[0].tag.Lore[1]",
+				"Inventory[1]", "Inventory[1].Slot", "Inventory[1].id", "Inventory[1].Count",
+				"Inventory[2]", "Inventory[2].Slot", "Inventory[2].id", "Inventory[2].Count"}, paths)
+		}
+	})
+	t.Run("should be ok and return paths usable with Get", func(t *testing.T) {
+		tag := newPathTag()
+
+		err := Walk(tag, func(path Path, elem Tag) error {
+			tags, err := path.Get(tag)
+			if assert.NoError(t, err) {
+				assert.Equal(t, []Tag{elem}, tags)
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+	t.Run("should be ok and skip the children", func(t *testing.T) {
+		var paths []string
+
+		err := Walk(newPathTag(), func(path Path, t Tag) error {
+			paths = append(paths, path.String())
+			if _, ok := t.(*ListT); ok {
+				return SkipChildren
+			}
+			return nil
+		})
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"", "Pos", `"dotted.name"`, "Seeds", "Inventory"}, paths)
+		}
+	})
+	t.Run("should be ok and stop early", func(t *testing.T) {
+		var paths []string
+
+		err := Walk(newPathTag(), func(path Path, t Tag) error {
+			paths = append(paths, path.String())
+			if path.String() == "Pos[1]" {
+				return SkipAll
+			}
+			return nil
+		})
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"", "Pos", "Pos[0]", "Pos[1]"}, paths)
+		}
+	})
+	t.Run("should return the error of the function", func(t *testing.T) {
+		expectedErr := errors.New("expected error")
+		var n int
+
+		err := Walk(newPathTag(), func(path Path, t Tag) error {
+			if n++; n == 3 {
+				return expectedErr
+			}
+			return nil
+		})
+		assert.Equal(t, expectedErr, err)
+		assert.Equal(t, 3, n)
+	})
+}
+
+func TestTransform(t *testing.T) {
+	t.Run("should be ok and replace the strings", func(t *testing.T) {
+		tag, err := Transform(newPathTag(), func(path Path, t Tag) (Tag, error) {
+			if str, ok := t.(*StringT); ok && str.Value == "minecraft:stone" {
+				return &StringT{Value: "minecraft:cobblestone"}, nil
+			}
+			return t, nil
+		})
+		if assert.NoError(t, err) {
+			tags, _ := MustParsePath(`Inventory[{id:"minecraft:cobblestone"}].Slot`).Get(tag)
+			assert.Equal(t, []Tag{&ByteT{Name: "Slot", Value: 0}, &ByteT{Name: "Slot", Value: 2}}, tags)
+			tags, _ = MustParsePath(`Inventory[0].id`).Get(tag)
+			assert.Equal(t, []Tag{&StringT{Name: "id", Value: "minecraft:cobblestone"}}, tags)
+		}
+	})
+	t.Run("should be ok and delete the tags", func(t *testing.T) {
+		tag, err := Transform(newPathTag(), func(path Path, t Tag) (Tag, error) {
+			if compound, ok := t.(*CompoundT); ok {
+				if id, _ := compound.GetString("id"); id == "minecraft:dirt" {
+					return nil, nil
+				}
+			}
+			if _, ok := t.(*LongArrayT); ok {
+				return nil, nil
+			}
+			return t, nil
+		})
+		if assert.NoError(t, err) {
+			inventory, _ := tag.(*CompoundT).GetList("Inventory")
+			assert.Equal(t, 2, inventory.Len())
+			_, ok := tag.(*CompoundT).Get("Seeds")
+			assert.False(t, ok)
+		}
+	})
+	t.Run("should be ok and change the type of all the elements of a list", func(t *testing.T) {
+		tag, err := Transform(newPathTag(), func(path Path, t Tag) (Tag, error) {
+			if d, ok := t.(*DoubleT); ok {
+				return &FloatT{Value: float32(d.Value)}, nil
+			}
+			return t, nil
+		})
+		if assert.NoError(t, err) {
+			pos, _ := tag.(*CompoundT).GetList("Pos")
+			assert.EqualValues(t, TagFloat, pos.ElemType)
+			assert.Equal(t, &FloatT{Value: 64}, pos.Value[1])
+		}
+	})
+	t.Run("should be ok and skip the children of the replaced tag", func(t *testing.T) {
+		var paths []string
+		list := &ListT{Value: []interface{}{&IntT{Value: 1}}}
+
+		tag, err := Transform(newPathTag(), func(path Path, t Tag) (Tag, error) {
+			paths = append(paths, path.String())
+			switch path.String() {
+			case "Inventory":
+				return list, SkipChildren
+			case "Seeds", "Pos":
+				return nil, nil
+			}
+			return t, nil
+		})
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"", "Pos", `"dotted.name"`, "Seeds", "Inventory"}, paths)
+			assert.Equal(t, []string{"dotted.name", "Inventory"}, tag.(*CompoundT).Keys())
+			assert.Equal(t, &ListT{Name: "Inventory", Value: []interface{}{&IntT{Value: 1}}}, list)
+		}
+	})
+	t.Run("should be ok and stop early", func(t *testing.T) {
+		var paths []string
+
+		tag, err := Transform(newPathTag(), func(path Path, t Tag) (Tag, error) {
+			paths = append(paths, path.String())
+			if path.String() == "Pos[1]" {
+				return &DoubleT{Value: 0}, SkipAll
+			}
+			return t, nil
+		})
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"", "Pos", "Pos[0]", "Pos[1]"}, paths)
+			tags, _ := MustParsePath(`Pos[]`).Get(tag)
+			assert.Equal(t, []Tag{&DoubleT{Value: 1.5}, &DoubleT{Value: 0}, &DoubleT{Value: -3}}, tags)
+		}
+	})
+	t.Run("should be ok and delete the root", func(t *testing.T) {
+		tag, err := Transform(newPathTag(), func(path Path, t Tag) (Tag, error) {
+			return nil, nil
+		})
+		if assert.NoError(t, err) {
+			assert.Nil(t, tag)
+		}
+	})
+	t.Run("should return an error because the elements of a list have different types", func(t *testing.T) {
+		tag := newPathTag()
+
+		_, err := Transform(tag, func(path Path, t Tag) (Tag, error) {
+			if path.String() == "Pos[1]" {
+				return &StringT{Value: "a"}, nil
+			}
+			return t, nil
+		})
+		assert.Equal(t, ErrListType, err)
+		pos, _ := tag.(*CompoundT).GetList("Pos")
+		assert.Equal(t, 3, pos.Len())
+	})
+	t.Run("should return the error of the function with the partially transformed root and keep the failed tag", func(t *testing.T) {
+		expectedErr := errors.New("expected error")
+		root := newPathTag()
+
+		tag, err := Transform(root, func(path Path, t Tag) (Tag, error) {
+			switch path.String() {
+			case `"dotted.name"`:
+				return &ByteT{Value: 9}, nil
+			case "Seeds":
+				return nil, expectedErr
+			}
+			return t, nil
+		})
+		assert.Equal(t, expectedErr, err)
+		if assert.Equal(t, root, tag) {
+			dotted, _ := tag.(*CompoundT).GetByte("dotted.name")
+			assert.EqualValues(t, 9, dotted)
+			assert.Equal(t, []string{"Pos", "dotted.name", "Seeds", "Inventory"}, tag.(*CompoundT).Keys())
+			seeds, _ := tag.(*CompoundT).Get("Seeds")
+			assert.Equal(t, &LongArrayT{Name: "Seeds", Value: []int64{1, 2, 3}}, seeds)
+		}
+	})
+}
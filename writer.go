@@ -2,6 +2,7 @@ package gonbt
 
 import (
 	"encoding/binary"
+	"errors"
 	"io"
 	"math"
 	"unsafe"
@@ -22,13 +23,24 @@ type Writer interface {
 }
 
 type writer struct {
-	flux io.Writer
+	flux  io.Writer
+	order binary.ByteOrder
+	// mutf8 encode the strings with the java modified UTF-8
+	mutf8 bool
 }
 
-// NewWriter nbt
+// NewWriter nbt with the java big-endian byte order
 func NewWriter(driver io.Writer) Writer {
+	return NewWriterOrder(driver, binary.BigEndian)
+}
+
+// NewWriterOrder nbt with the byte order, binary.LittleEndian for the bedrock edition.
+// The big-endian strings of the java edition are encoded with the modified UTF-8
+func NewWriterOrder(driver io.Writer, order binary.ByteOrder) Writer {
 	return &writer{
-		flux: driver,
+		flux:  driver,
+		order: order,
+		mutf8: order == binary.BigEndian,
 	}
 }
 
@@ -37,13 +49,20 @@ func (w *writer) String(str string) error {
 	var size uint16
 	var err error
 
+	data := []byte(str)
+	if w.mutf8 {
+		data = encodeMUTF8(str)
+	}
+	if len(data) > math.MaxUint16 {
+		return errors.New(errorStringLength)
+	}
 	bsize := make([]byte, unsafe.Sizeof(size))
-	size = uint16(len(str))
-	binary.BigEndian.PutUint16(bsize, size)
+	size = uint16(len(data))
+	w.order.PutUint16(bsize, size)
 	if _, err = w.flux.Write(bsize); err != nil {
 		return err
 	}
-	if _, err = w.flux.Write([]byte(str)); err != nil {
+	if _, err = w.flux.Write(data); err != nil {
 		return err
 	}
 	return nil
@@ -64,7 +83,7 @@ func (w *writer) Short(v int16) error {
 	var err error
 
 	b := make([]byte, unsafe.Sizeof(v))
-	binary.BigEndian.PutUint16(b, uint16(v))
+	w.order.PutUint16(b, uint16(v))
 	if _, err = w.flux.Write(b); err != nil {
 		return err
 	}
@@ -77,7 +96,7 @@ func (w *writer) Int(v int32) error {
 	var err error
 
 	b := make([]byte, unsafe.Sizeof(size))
-	binary.BigEndian.PutUint32(b, uint32(v))
+	w.order.PutUint32(b, uint32(v))
 	if _, err = w.flux.Write(b); err != nil {
 		return err
 	}
@@ -90,7 +109,7 @@ func (w *writer) Long(v int64) error {
 	var err error
 
 	b := make([]byte, unsafe.Sizeof(size))
-	binary.BigEndian.PutUint64(b, uint64(v))
+	w.order.PutUint64(b, uint64(v))
 	if _, err = w.flux.Write(b); err != nil {
 		return err
 	}
@@ -103,7 +122,7 @@ func (w *writer) Float(v float32) error {
 	var err error
 
 	b := make([]byte, unsafe.Sizeof(size))
-	binary.BigEndian.PutUint32(b, math.Float32bits(v))
+	w.order.PutUint32(b, math.Float32bits(v))
 	if _, err = w.flux.Write(b); err != nil {
 		return err
 	}
@@ -116,7 +135,7 @@ func (w *writer) Double(v float64) error {
 	var err error
 
 	b := make([]byte, unsafe.Sizeof(size))
-	binary.BigEndian.PutUint64(b, math.Float64bits(v))
+	w.order.PutUint64(b, math.Float64bits(v))
 	if _, err = w.flux.Write(b); err != nil {
 		return err
 	}
@@ -130,7 +149,7 @@ func (w *writer) Bytes(v []byte) error {
 
 	bsize := make([]byte, unsafe.Sizeof(size))
 	size = int32(len(v))
-	binary.BigEndian.PutUint32(bsize, uint32(size))
+	w.order.PutUint32(bsize, uint32(size))
 	if _, err = w.flux.Write(bsize); err != nil {
 		return err
 	}
@@ -142,44 +161,32 @@ func (w *writer) Bytes(v []byte) error {
 
 // IntArray write with nbt format
 func (w *writer) IntArray(values []int32) error {
-	var size int32
 	var err error
 
-	// get number element
-	buf := make([]byte, unsafe.Sizeof(size))
-	size = int32(len(values))
-	binary.BigEndian.PutUint32(buf, uint32(size))
+	// the number of elements and the values in one buffer
+	buf := make([]byte, 4+4*len(values))
+	w.order.PutUint32(buf, uint32(len(values)))
+	for i, v := range values {
+		w.order.PutUint32(buf[4+4*i:], uint32(v))
+	}
 	if _, err = w.flux.Write(buf); err != nil {
 		return err
 	}
-	for _, v := range values {
-		buf = make([]byte, unsafe.Sizeof(v))
-		binary.BigEndian.PutUint32(buf, uint32(v))
-		if _, err = w.flux.Write(buf); err != nil {
-			return err
-		}
-	}
 	return nil
 }
 
 // LongArray write with nbt format
 func (w *writer) LongArray(values []int64) error {
-	var size int64
 	var err error
 
-	// get number element
-	buf := make([]byte, unsafe.Sizeof(size))
-	size = int64(len(values))
-	binary.BigEndian.PutUint64(buf, uint64(size))
+	// the number of elements and the values in one buffer
+	buf := make([]byte, 4+8*len(values))
+	w.order.PutUint32(buf, uint32(len(values)))
+	for i, v := range values {
+		w.order.PutUint64(buf[4+8*i:], uint64(v))
+	}
 	if _, err = w.flux.Write(buf); err != nil {
 		return err
 	}
-	for _, v := range values {
-		buf = make([]byte, unsafe.Sizeof(v))
-		binary.BigEndian.PutUint64(buf, uint64(v))
-		if _, err = w.flux.Write(buf); err != nil {
-			return err
-		}
-	}
 	return nil
 }
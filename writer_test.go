@@ -2,6 +2,7 @@ package gonbt
 
 import (
 	"bytes"
+	"encoding/binary"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,7 +14,7 @@ func TestWriter_String(t *testing.T) {
 	t.Run("should be ok with a full string", func(t *testing.T) {
 		data := []byte{}
 		r := bytes.NewBuffer(data)
-		w := &writer{flux: r}
+		w := &writer{flux: r, order: binary.BigEndian}
 
 		expectedByte := append([]byte{0x00, 0x0d}, []byte("Hello World !")...)
 		err := w.String("Hello World !")
@@ -24,7 +25,7 @@ func TestWriter_String(t *testing.T) {
 	t.Run("should be ok with an empty string", func(t *testing.T) {
 		data := []byte{}
 		r := bytes.NewBuffer(data)
-		w := &writer{flux: r}
+		w := &writer{flux: r, order: binary.BigEndian}
 
 		expectedByte := []byte{0x00, 0x00}
 		err := w.String("")
@@ -39,9 +40,9 @@ func TestWriter_Byte(t *testing.T) {
 	t.Run("should be ok", func(t *testing.T) {
 		data := []byte{}
 		r := bytes.NewBuffer(data)
-		w := &writer{flux: r}
+		w := &writer{flux: r, order: binary.BigEndian}
 
-		expectedByte := append([]byte{'A'})
+		expectedByte := []byte{'A'}
 		err := w.Byte(byte('A'))
 		if assert.NoError(t, err) {
 			assert.EqualValues(t, expectedByte, r.Bytes())
@@ -54,7 +55,7 @@ func TestWriter_Short(t *testing.T) {
 	t.Run("should be ok", func(t *testing.T) {
 		data := []byte{}
 		r := bytes.NewBuffer(data)
-		w := &writer{flux: r}
+		w := &writer{flux: r, order: binary.BigEndian}
 
 		expectedByte := []byte{0x00, 0x2a}
 		err := w.Short(int16(42))
@@ -69,7 +70,7 @@ func TestWriter_Int(t *testing.T) {
 	t.Run("should be ok", func(t *testing.T) {
 		data := []byte{}
 		r := bytes.NewBuffer(data)
-		w := &writer{flux: r}
+		w := &writer{flux: r, order: binary.BigEndian}
 
 		expectedByte := []byte{0x00, 0x00, 0x00, 0x2a}
 		err := w.Int(int32(42))
@@ -84,7 +85,7 @@ func TestWriter_Long(t *testing.T) {
 	t.Run("should be ok", func(t *testing.T) {
 		data := []byte{}
 		r := bytes.NewBuffer(data)
-		w := &writer{flux: r}
+		w := &writer{flux: r, order: binary.BigEndian}
 
 		expectedByte := []byte{0x00, 0x00, 0x00, 0x00, 0x0a, 0x0a, 0x00, 0x0a}
 		err := w.Long(int64(168427530))
@@ -99,7 +100,7 @@ func TestWriter_Float(t *testing.T) {
 	t.Run("should be ok", func(t *testing.T) {
 		data := []byte{}
 		r := bytes.NewBuffer(data)
-		w := &writer{flux: r}
+		w := &writer{flux: r, order: binary.BigEndian}
 
 		expectedByte := []byte{0x42, 0x28, 0xcc, 0xcd}
 		err := w.Float(42.2)
@@ -114,7 +115,7 @@ func TestWriter_Double(t *testing.T) {
 	t.Run("should be ok", func(t *testing.T) {
 		data := []byte{}
 		r := bytes.NewBuffer(data)
-		w := &writer{flux: r}
+		w := &writer{flux: r, order: binary.BigEndian}
 
 		expectedByte := []byte{0x40, 0x45, 0x19, 0x99, 0x99, 0x99, 0x99, 0x9a}
 		err := w.Double(42.2)
@@ -129,7 +130,7 @@ func TestWriter_Bytes(t *testing.T) {
 	t.Run("should be ok with a full string", func(t *testing.T) {
 		data := []byte{}
 		r := bytes.NewBuffer(data)
-		w := &writer{flux: r}
+		w := &writer{flux: r, order: binary.BigEndian}
 
 		expectedByte := append([]byte{0x00, 0x00, 0x00, 0x0d}, []byte("Hello World !")...)
 		err := w.Bytes([]byte("Hello World !"))
@@ -140,7 +141,7 @@ func TestWriter_Bytes(t *testing.T) {
 	t.Run("should be ok with an empty string", func(t *testing.T) {
 		data := []byte{}
 		r := bytes.NewBuffer(data)
-		w := &writer{flux: r}
+		w := &writer{flux: r, order: binary.BigEndian}
 
 		expectedByte := []byte{0x00, 0x00, 0x00, 0x00}
 		err := w.Bytes([]byte{})
@@ -155,7 +156,7 @@ func TestWriter_IntArray(t *testing.T) {
 	t.Run("should be ok with an empty list", func(t *testing.T) {
 		data := []byte{}
 		r := bytes.NewBuffer(data)
-		w := &writer{flux: r}
+		w := &writer{flux: r, order: binary.BigEndian}
 
 		expectedByte := []byte{0x00, 0x00, 0x00, 0x00}
 		err := w.IntArray([]int32{})
@@ -166,7 +167,7 @@ func TestWriter_IntArray(t *testing.T) {
 	t.Run("should be ok with 3 elements", func(t *testing.T) {
 		data := []byte{}
 		r := bytes.NewBuffer(data)
-		w := &writer{flux: r}
+		w := &writer{flux: r, order: binary.BigEndian}
 
 		expectedByte := []byte{0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x0b, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x00, 0x00, 0x2a}
 		err := w.IntArray([]int32{11, 10, 42})
@@ -181,9 +182,9 @@ func TestWriter_LongArray(t *testing.T) {
 	t.Run("should be ok with an empty list", func(t *testing.T) {
 		data := []byte{}
 		r := bytes.NewBuffer(data)
-		w := &writer{flux: r}
+		w := &writer{flux: r, order: binary.BigEndian}
 
-		expectedByte := []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+		expectedByte := []byte{0x00, 0x00, 0x00, 0x00}
 		err := w.LongArray([]int64{})
 		if assert.NoError(t, err) {
 			assert.EqualValues(t, expectedByte, r.Bytes())
@@ -192,9 +193,9 @@ func TestWriter_LongArray(t *testing.T) {
 	t.Run("should be ok with 3 elements", func(t *testing.T) {
 		data := []byte{}
 		r := bytes.NewBuffer(data)
-		w := &writer{flux: r}
+		w := &writer{flux: r, order: binary.BigEndian}
 
-		expectedByte := []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03}
+		expectedByte := []byte{0x00, 0x00, 0x00, 0x03}
 		expectedByte = append(expectedByte, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0b}...)
 		expectedByte = append(expectedByte, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a}...)
 		expectedByte = append(expectedByte, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x2a}...)